@@ -0,0 +1,1379 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: people/v1/people.proto
+
+package peoplev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Person struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName   string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	MiddleName  *string                `protobuf:"bytes,3,opt,name=middle_name,json=middleName,proto3,oneof" json:"middle_name,omitempty"`
+	LastName    string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Gender      *string                `protobuf:"bytes,5,opt,name=gender,proto3,oneof" json:"gender,omitempty"`
+	Nationality *string                `protobuf:"bytes,6,opt,name=nationality,proto3,oneof" json:"nationality,omitempty"`
+	Age         *int32                 `protobuf:"varint,7,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Only filled by GetPerson, CreatePerson and UpdatePerson.
+	Emails        []*Email `protobuf:"bytes,10,rep,name=emails,proto3" json:"emails,omitempty"`
+	FriendsCount  int32    `protobuf:"varint,11,opt,name=friends_count,json=friendsCount,proto3" json:"friends_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Person) Reset() {
+	*x = Person{}
+	mi := &file_people_v1_people_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Person) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Person) ProtoMessage() {}
+
+func (x *Person) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Person.ProtoReflect.Descriptor instead.
+func (*Person) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Person) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Person) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Person) GetMiddleName() string {
+	if x != nil && x.MiddleName != nil {
+		return *x.MiddleName
+	}
+	return ""
+}
+
+func (x *Person) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Person) GetGender() string {
+	if x != nil && x.Gender != nil {
+		return *x.Gender
+	}
+	return ""
+}
+
+func (x *Person) GetNationality() string {
+	if x != nil && x.Nationality != nil {
+		return *x.Nationality
+	}
+	return ""
+}
+
+func (x *Person) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *Person) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Person) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Person) GetEmails() []*Email {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+func (x *Person) GetFriendsCount() int32 {
+	if x != nil {
+		return x.FriendsCount
+	}
+	return 0
+}
+
+type Email struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PersonId      int64                  `protobuf:"varint,2,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,4,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Email) Reset() {
+	*x = Email{}
+	mi := &file_people_v1_people_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Email) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Email) ProtoMessage() {}
+
+func (x *Email) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Email.ProtoReflect.Descriptor instead.
+func (*Email) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Email) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Email) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *Email) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Email) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *Email) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Friendship struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FriendId      int64                  `protobuf:"varint,2,opt,name=friend_id,json=friendId,proto3" json:"friend_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Friendship) Reset() {
+	*x = Friendship{}
+	mi := &file_people_v1_people_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Friendship) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Friendship) ProtoMessage() {}
+
+func (x *Friendship) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Friendship.ProtoReflect.Descriptor instead.
+func (*Friendship) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Friendship) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Friendship) GetFriendId() int64 {
+	if x != nil {
+		return x.FriendId
+	}
+	return 0
+}
+
+func (x *Friendship) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// PeopleFilter matches people on every non-empty field, case-insensitively.
+type PeopleFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastName      string                 `protobuf:"bytes,1,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Nationality   string                 `protobuf:"bytes,2,opt,name=nationality,proto3" json:"nationality,omitempty"`
+	Gender        string                 `protobuf:"bytes,3,opt,name=gender,proto3" json:"gender,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeopleFilter) Reset() {
+	*x = PeopleFilter{}
+	mi := &file_people_v1_people_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeopleFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeopleFilter) ProtoMessage() {}
+
+func (x *PeopleFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeopleFilter.ProtoReflect.Descriptor instead.
+func (*PeopleFilter) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PeopleFilter) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *PeopleFilter) GetNationality() string {
+	if x != nil {
+		return x.Nationality
+	}
+	return ""
+}
+
+func (x *PeopleFilter) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+type GetPersonRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPersonRequest) Reset() {
+	*x = GetPersonRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPersonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPersonRequest) ProtoMessage() {}
+
+func (x *GetPersonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPersonRequest.ProtoReflect.Descriptor instead.
+func (*GetPersonRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPersonRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListPeopleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *PeopleFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPeopleRequest) Reset() {
+	*x = ListPeopleRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPeopleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeopleRequest) ProtoMessage() {}
+
+func (x *ListPeopleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeopleRequest.ProtoReflect.Descriptor instead.
+func (*ListPeopleRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListPeopleRequest) GetFilter() *PeopleFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type NewEmail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,2,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewEmail) Reset() {
+	*x = NewEmail{}
+	mi := &file_people_v1_people_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewEmail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewEmail) ProtoMessage() {}
+
+func (x *NewEmail) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewEmail.ProtoReflect.Descriptor instead.
+func (*NewEmail) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NewEmail) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *NewEmail) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+// Unset gender, nationality and age are inferred from the first name.
+type CreatePersonRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	MiddleName    *string                `protobuf:"bytes,2,opt,name=middle_name,json=middleName,proto3,oneof" json:"middle_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Gender        *string                `protobuf:"bytes,4,opt,name=gender,proto3,oneof" json:"gender,omitempty"`
+	Nationality   *string                `protobuf:"bytes,5,opt,name=nationality,proto3,oneof" json:"nationality,omitempty"`
+	Age           *int32                 `protobuf:"varint,6,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	Emails        []*NewEmail            `protobuf:"bytes,7,rep,name=emails,proto3" json:"emails,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePersonRequest) Reset() {
+	*x = CreatePersonRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePersonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePersonRequest) ProtoMessage() {}
+
+func (x *CreatePersonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePersonRequest.ProtoReflect.Descriptor instead.
+func (*CreatePersonRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreatePersonRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *CreatePersonRequest) GetMiddleName() string {
+	if x != nil && x.MiddleName != nil {
+		return *x.MiddleName
+	}
+	return ""
+}
+
+func (x *CreatePersonRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *CreatePersonRequest) GetGender() string {
+	if x != nil && x.Gender != nil {
+		return *x.Gender
+	}
+	return ""
+}
+
+func (x *CreatePersonRequest) GetNationality() string {
+	if x != nil && x.Nationality != nil {
+		return *x.Nationality
+	}
+	return ""
+}
+
+func (x *CreatePersonRequest) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *CreatePersonRequest) GetEmails() []*NewEmail {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+// Only the fields that are set are changed.
+type UpdatePersonRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName     *string                `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3,oneof" json:"first_name,omitempty"`
+	MiddleName    *string                `protobuf:"bytes,3,opt,name=middle_name,json=middleName,proto3,oneof" json:"middle_name,omitempty"`
+	LastName      *string                `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3,oneof" json:"last_name,omitempty"`
+	Gender        *string                `protobuf:"bytes,5,opt,name=gender,proto3,oneof" json:"gender,omitempty"`
+	Nationality   *string                `protobuf:"bytes,6,opt,name=nationality,proto3,oneof" json:"nationality,omitempty"`
+	Age           *int32                 `protobuf:"varint,7,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePersonRequest) Reset() {
+	*x = UpdatePersonRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePersonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePersonRequest) ProtoMessage() {}
+
+func (x *UpdatePersonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePersonRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePersonRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdatePersonRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePersonRequest) GetFirstName() string {
+	if x != nil && x.FirstName != nil {
+		return *x.FirstName
+	}
+	return ""
+}
+
+func (x *UpdatePersonRequest) GetMiddleName() string {
+	if x != nil && x.MiddleName != nil {
+		return *x.MiddleName
+	}
+	return ""
+}
+
+func (x *UpdatePersonRequest) GetLastName() string {
+	if x != nil && x.LastName != nil {
+		return *x.LastName
+	}
+	return ""
+}
+
+func (x *UpdatePersonRequest) GetGender() string {
+	if x != nil && x.Gender != nil {
+		return *x.Gender
+	}
+	return ""
+}
+
+func (x *UpdatePersonRequest) GetNationality() string {
+	if x != nil && x.Nationality != nil {
+		return *x.Nationality
+	}
+	return ""
+}
+
+func (x *UpdatePersonRequest) GetAge() int32 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+type ListEmailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailsRequest) Reset() {
+	*x = ListEmailsRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailsRequest) ProtoMessage() {}
+
+func (x *ListEmailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailsRequest.ProtoReflect.Descriptor instead.
+func (*ListEmailsRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListEmailsRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+type ListEmailsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emails        []*Email               `protobuf:"bytes,1,rep,name=emails,proto3" json:"emails,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailsResponse) Reset() {
+	*x = ListEmailsResponse{}
+	mi := &file_people_v1_people_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailsResponse) ProtoMessage() {}
+
+func (x *ListEmailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailsResponse.ProtoReflect.Descriptor instead.
+func (*ListEmailsResponse) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListEmailsResponse) GetEmails() []*Email {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+type AddEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,3,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddEmailRequest) Reset() {
+	*x = AddEmailRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddEmailRequest) ProtoMessage() {}
+
+func (x *AddEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddEmailRequest.ProtoReflect.Descriptor instead.
+func (*AddEmailRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddEmailRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *AddEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *AddEmailRequest) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+type DeleteEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	EmailId       int64                  `protobuf:"varint,2,opt,name=email_id,json=emailId,proto3" json:"email_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEmailRequest) Reset() {
+	*x = DeleteEmailRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEmailRequest) ProtoMessage() {}
+
+func (x *DeleteEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEmailRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEmailRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteEmailRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *DeleteEmailRequest) GetEmailId() int64 {
+	if x != nil {
+		return x.EmailId
+	}
+	return 0
+}
+
+type ListFriendsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFriendsRequest) Reset() {
+	*x = ListFriendsRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFriendsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFriendsRequest) ProtoMessage() {}
+
+func (x *ListFriendsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFriendsRequest.ProtoReflect.Descriptor instead.
+func (*ListFriendsRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListFriendsRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+type AddFriendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	FriendId      int64                  `protobuf:"varint,2,opt,name=friend_id,json=friendId,proto3" json:"friend_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddFriendRequest) Reset() {
+	*x = AddFriendRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddFriendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddFriendRequest) ProtoMessage() {}
+
+func (x *AddFriendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddFriendRequest.ProtoReflect.Descriptor instead.
+func (*AddFriendRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AddFriendRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *AddFriendRequest) GetFriendId() int64 {
+	if x != nil {
+		return x.FriendId
+	}
+	return 0
+}
+
+type RemoveFriendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      int64                  `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	FriendId      int64                  `protobuf:"varint,2,opt,name=friend_id,json=friendId,proto3" json:"friend_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveFriendRequest) Reset() {
+	*x = RemoveFriendRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveFriendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveFriendRequest) ProtoMessage() {}
+
+func (x *RemoveFriendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveFriendRequest.ProtoReflect.Descriptor instead.
+func (*RemoveFriendRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RemoveFriendRequest) GetPersonId() int64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *RemoveFriendRequest) GetFriendId() int64 {
+	if x != nil {
+		return x.FriendId
+	}
+	return 0
+}
+
+type ExportGraphRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *PeopleFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportGraphRequest) Reset() {
+	*x = ExportGraphRequest{}
+	mi := &file_people_v1_people_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportGraphRequest) ProtoMessage() {}
+
+func (x *ExportGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportGraphRequest.ProtoReflect.Descriptor instead.
+func (*ExportGraphRequest) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportGraphRequest) GetFilter() *PeopleFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GraphElement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Element:
+	//
+	//	*GraphElement_Person
+	//	*GraphElement_Friendship
+	Element       isGraphElement_Element `protobuf_oneof:"element"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphElement) Reset() {
+	*x = GraphElement{}
+	mi := &file_people_v1_people_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GraphElement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphElement) ProtoMessage() {}
+
+func (x *GraphElement) ProtoReflect() protoreflect.Message {
+	mi := &file_people_v1_people_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphElement.ProtoReflect.Descriptor instead.
+func (*GraphElement) Descriptor() ([]byte, []int) {
+	return file_people_v1_people_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GraphElement) GetElement() isGraphElement_Element {
+	if x != nil {
+		return x.Element
+	}
+	return nil
+}
+
+func (x *GraphElement) GetPerson() *Person {
+	if x != nil {
+		if x, ok := x.Element.(*GraphElement_Person); ok {
+			return x.Person
+		}
+	}
+	return nil
+}
+
+func (x *GraphElement) GetFriendship() *Friendship {
+	if x != nil {
+		if x, ok := x.Element.(*GraphElement_Friendship); ok {
+			return x.Friendship
+		}
+	}
+	return nil
+}
+
+type isGraphElement_Element interface {
+	isGraphElement_Element()
+}
+
+type GraphElement_Person struct {
+	Person *Person `protobuf:"bytes,1,opt,name=person,proto3,oneof"`
+}
+
+type GraphElement_Friendship struct {
+	Friendship *Friendship `protobuf:"bytes,2,opt,name=friendship,proto3,oneof"`
+}
+
+func (*GraphElement_Person) isGraphElement_Element() {}
+
+func (*GraphElement_Friendship) isGraphElement_Element() {}
+
+var File_people_v1_people_proto protoreflect.FileDescriptor
+
+const file_people_v1_people_proto_rawDesc = "" +
+	"\n" +
+	"\x16people/v1/people.proto\x12\tpeople.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcd\x03\n" +
+	"\x06Person\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12$\n" +
+	"\vmiddle_name\x18\x03 \x01(\tH\x00R\n" +
+	"middleName\x88\x01\x01\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x1b\n" +
+	"\x06gender\x18\x05 \x01(\tH\x01R\x06gender\x88\x01\x01\x12%\n" +
+	"\vnationality\x18\x06 \x01(\tH\x02R\vnationality\x88\x01\x01\x12\x15\n" +
+	"\x03age\x18\a \x01(\x05H\x03R\x03age\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12(\n" +
+	"\x06emails\x18\n" +
+	" \x03(\v2\x10.people.v1.EmailR\x06emails\x12#\n" +
+	"\rfriends_count\x18\v \x01(\x05R\ffriendsCountB\x0e\n" +
+	"\f_middle_nameB\t\n" +
+	"\a_genderB\x0e\n" +
+	"\f_nationalityB\x06\n" +
+	"\x04_age\"\xa4\x01\n" +
+	"\x05Email\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tperson_id\x18\x02 \x01(\x03R\bpersonId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x04 \x01(\bR\tisPrimary\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"}\n" +
+	"\n" +
+	"Friendship\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfriend_id\x18\x02 \x01(\x03R\bfriendId\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"e\n" +
+	"\fPeopleFilter\x12\x1b\n" +
+	"\tlast_name\x18\x01 \x01(\tR\blastName\x12 \n" +
+	"\vnationality\x18\x02 \x01(\tR\vnationality\x12\x16\n" +
+	"\x06gender\x18\x03 \x01(\tR\x06gender\"\"\n" +
+	"\x10GetPersonRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"D\n" +
+	"\x11ListPeopleRequest\x12/\n" +
+	"\x06filter\x18\x01 \x01(\v2\x17.people.v1.PeopleFilterR\x06filter\"?\n" +
+	"\bNewEmail\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x02 \x01(\bR\tisPrimary\"\xb2\x02\n" +
+	"\x13CreatePersonRequest\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12$\n" +
+	"\vmiddle_name\x18\x02 \x01(\tH\x00R\n" +
+	"middleName\x88\x01\x01\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x1b\n" +
+	"\x06gender\x18\x04 \x01(\tH\x01R\x06gender\x88\x01\x01\x12%\n" +
+	"\vnationality\x18\x05 \x01(\tH\x02R\vnationality\x88\x01\x01\x12\x15\n" +
+	"\x03age\x18\x06 \x01(\x05H\x03R\x03age\x88\x01\x01\x12+\n" +
+	"\x06emails\x18\a \x03(\v2\x13.people.v1.NewEmailR\x06emailsB\x0e\n" +
+	"\f_middle_nameB\t\n" +
+	"\a_genderB\x0e\n" +
+	"\f_nationalityB\x06\n" +
+	"\x04_age\"\xbc\x02\n" +
+	"\x13UpdatePersonRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\"\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tH\x00R\tfirstName\x88\x01\x01\x12$\n" +
+	"\vmiddle_name\x18\x03 \x01(\tH\x01R\n" +
+	"middleName\x88\x01\x01\x12 \n" +
+	"\tlast_name\x18\x04 \x01(\tH\x02R\blastName\x88\x01\x01\x12\x1b\n" +
+	"\x06gender\x18\x05 \x01(\tH\x03R\x06gender\x88\x01\x01\x12%\n" +
+	"\vnationality\x18\x06 \x01(\tH\x04R\vnationality\x88\x01\x01\x12\x15\n" +
+	"\x03age\x18\a \x01(\x05H\x05R\x03age\x88\x01\x01B\r\n" +
+	"\v_first_nameB\x0e\n" +
+	"\f_middle_nameB\f\n" +
+	"\n" +
+	"_last_nameB\t\n" +
+	"\a_genderB\x0e\n" +
+	"\f_nationalityB\x06\n" +
+	"\x04_age\"0\n" +
+	"\x11ListEmailsRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\">\n" +
+	"\x12ListEmailsResponse\x12(\n" +
+	"\x06emails\x18\x01 \x03(\v2\x10.people.v1.EmailR\x06emails\"c\n" +
+	"\x0fAddEmailRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x03 \x01(\bR\tisPrimary\"L\n" +
+	"\x12DeleteEmailRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\x12\x19\n" +
+	"\bemail_id\x18\x02 \x01(\x03R\aemailId\"1\n" +
+	"\x12ListFriendsRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\"L\n" +
+	"\x10AddFriendRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\x12\x1b\n" +
+	"\tfriend_id\x18\x02 \x01(\x03R\bfriendId\"O\n" +
+	"\x13RemoveFriendRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x03R\bpersonId\x12\x1b\n" +
+	"\tfriend_id\x18\x02 \x01(\x03R\bfriendId\"E\n" +
+	"\x12ExportGraphRequest\x12/\n" +
+	"\x06filter\x18\x01 \x01(\v2\x17.people.v1.PeopleFilterR\x06filter\"\x7f\n" +
+	"\fGraphElement\x12+\n" +
+	"\x06person\x18\x01 \x01(\v2\x11.people.v1.PersonH\x00R\x06person\x127\n" +
+	"\n" +
+	"friendship\x18\x02 \x01(\v2\x15.people.v1.FriendshipH\x00R\n" +
+	"friendshipB\t\n" +
+	"\aelement2\xf4\x05\n" +
+	"\rPeopleService\x12;\n" +
+	"\tGetPerson\x12\x1b.people.v1.GetPersonRequest\x1a\x11.people.v1.Person\x12?\n" +
+	"\n" +
+	"ListPeople\x12\x1c.people.v1.ListPeopleRequest\x1a\x11.people.v1.Person0\x01\x12A\n" +
+	"\fCreatePerson\x12\x1e.people.v1.CreatePersonRequest\x1a\x11.people.v1.Person\x12A\n" +
+	"\fUpdatePerson\x12\x1e.people.v1.UpdatePersonRequest\x1a\x11.people.v1.Person\x12I\n" +
+	"\n" +
+	"ListEmails\x12\x1c.people.v1.ListEmailsRequest\x1a\x1d.people.v1.ListEmailsResponse\x128\n" +
+	"\bAddEmail\x12\x1a.people.v1.AddEmailRequest\x1a\x10.people.v1.Email\x12D\n" +
+	"\vDeleteEmail\x12\x1d.people.v1.DeleteEmailRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\vListFriends\x12\x1d.people.v1.ListFriendsRequest\x1a\x11.people.v1.Person0\x01\x12@\n" +
+	"\tAddFriend\x12\x1b.people.v1.AddFriendRequest\x1a\x16.google.protobuf.Empty\x12F\n" +
+	"\fRemoveFriend\x12\x1e.people.v1.RemoveFriendRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\vExportGraph\x12\x1d.people.v1.ExportGraphRequest\x1a\x17.people.v1.GraphElement0\x01B=Z;github.com/Kirill-Pinyaev/people-api/api/people/v1;peoplev1b\x06proto3"
+
+var (
+	file_people_v1_people_proto_rawDescOnce sync.Once
+	file_people_v1_people_proto_rawDescData []byte
+)
+
+func file_people_v1_people_proto_rawDescGZIP() []byte {
+	file_people_v1_people_proto_rawDescOnce.Do(func() {
+		file_people_v1_people_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_people_v1_people_proto_rawDesc), len(file_people_v1_people_proto_rawDesc)))
+	})
+	return file_people_v1_people_proto_rawDescData
+}
+
+var file_people_v1_people_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_people_v1_people_proto_goTypes = []any{
+	(*Person)(nil),                // 0: people.v1.Person
+	(*Email)(nil),                 // 1: people.v1.Email
+	(*Friendship)(nil),            // 2: people.v1.Friendship
+	(*PeopleFilter)(nil),          // 3: people.v1.PeopleFilter
+	(*GetPersonRequest)(nil),      // 4: people.v1.GetPersonRequest
+	(*ListPeopleRequest)(nil),     // 5: people.v1.ListPeopleRequest
+	(*NewEmail)(nil),              // 6: people.v1.NewEmail
+	(*CreatePersonRequest)(nil),   // 7: people.v1.CreatePersonRequest
+	(*UpdatePersonRequest)(nil),   // 8: people.v1.UpdatePersonRequest
+	(*ListEmailsRequest)(nil),     // 9: people.v1.ListEmailsRequest
+	(*ListEmailsResponse)(nil),    // 10: people.v1.ListEmailsResponse
+	(*AddEmailRequest)(nil),       // 11: people.v1.AddEmailRequest
+	(*DeleteEmailRequest)(nil),    // 12: people.v1.DeleteEmailRequest
+	(*ListFriendsRequest)(nil),    // 13: people.v1.ListFriendsRequest
+	(*AddFriendRequest)(nil),      // 14: people.v1.AddFriendRequest
+	(*RemoveFriendRequest)(nil),   // 15: people.v1.RemoveFriendRequest
+	(*ExportGraphRequest)(nil),    // 16: people.v1.ExportGraphRequest
+	(*GraphElement)(nil),          // 17: people.v1.GraphElement
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 19: google.protobuf.Empty
+}
+var file_people_v1_people_proto_depIdxs = []int32{
+	18, // 0: people.v1.Person.created_at:type_name -> google.protobuf.Timestamp
+	18, // 1: people.v1.Person.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: people.v1.Person.emails:type_name -> people.v1.Email
+	18, // 3: people.v1.Email.created_at:type_name -> google.protobuf.Timestamp
+	18, // 4: people.v1.Friendship.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: people.v1.ListPeopleRequest.filter:type_name -> people.v1.PeopleFilter
+	6,  // 6: people.v1.CreatePersonRequest.emails:type_name -> people.v1.NewEmail
+	1,  // 7: people.v1.ListEmailsResponse.emails:type_name -> people.v1.Email
+	3,  // 8: people.v1.ExportGraphRequest.filter:type_name -> people.v1.PeopleFilter
+	0,  // 9: people.v1.GraphElement.person:type_name -> people.v1.Person
+	2,  // 10: people.v1.GraphElement.friendship:type_name -> people.v1.Friendship
+	4,  // 11: people.v1.PeopleService.GetPerson:input_type -> people.v1.GetPersonRequest
+	5,  // 12: people.v1.PeopleService.ListPeople:input_type -> people.v1.ListPeopleRequest
+	7,  // 13: people.v1.PeopleService.CreatePerson:input_type -> people.v1.CreatePersonRequest
+	8,  // 14: people.v1.PeopleService.UpdatePerson:input_type -> people.v1.UpdatePersonRequest
+	9,  // 15: people.v1.PeopleService.ListEmails:input_type -> people.v1.ListEmailsRequest
+	11, // 16: people.v1.PeopleService.AddEmail:input_type -> people.v1.AddEmailRequest
+	12, // 17: people.v1.PeopleService.DeleteEmail:input_type -> people.v1.DeleteEmailRequest
+	13, // 18: people.v1.PeopleService.ListFriends:input_type -> people.v1.ListFriendsRequest
+	14, // 19: people.v1.PeopleService.AddFriend:input_type -> people.v1.AddFriendRequest
+	15, // 20: people.v1.PeopleService.RemoveFriend:input_type -> people.v1.RemoveFriendRequest
+	16, // 21: people.v1.PeopleService.ExportGraph:input_type -> people.v1.ExportGraphRequest
+	0,  // 22: people.v1.PeopleService.GetPerson:output_type -> people.v1.Person
+	0,  // 23: people.v1.PeopleService.ListPeople:output_type -> people.v1.Person
+	0,  // 24: people.v1.PeopleService.CreatePerson:output_type -> people.v1.Person
+	0,  // 25: people.v1.PeopleService.UpdatePerson:output_type -> people.v1.Person
+	10, // 26: people.v1.PeopleService.ListEmails:output_type -> people.v1.ListEmailsResponse
+	1,  // 27: people.v1.PeopleService.AddEmail:output_type -> people.v1.Email
+	19, // 28: people.v1.PeopleService.DeleteEmail:output_type -> google.protobuf.Empty
+	0,  // 29: people.v1.PeopleService.ListFriends:output_type -> people.v1.Person
+	19, // 30: people.v1.PeopleService.AddFriend:output_type -> google.protobuf.Empty
+	19, // 31: people.v1.PeopleService.RemoveFriend:output_type -> google.protobuf.Empty
+	17, // 32: people.v1.PeopleService.ExportGraph:output_type -> people.v1.GraphElement
+	22, // [22:33] is the sub-list for method output_type
+	11, // [11:22] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_people_v1_people_proto_init() }
+func file_people_v1_people_proto_init() {
+	if File_people_v1_people_proto != nil {
+		return
+	}
+	file_people_v1_people_proto_msgTypes[0].OneofWrappers = []any{}
+	file_people_v1_people_proto_msgTypes[7].OneofWrappers = []any{}
+	file_people_v1_people_proto_msgTypes[8].OneofWrappers = []any{}
+	file_people_v1_people_proto_msgTypes[17].OneofWrappers = []any{
+		(*GraphElement_Person)(nil),
+		(*GraphElement_Friendship)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_people_v1_people_proto_rawDesc), len(file_people_v1_people_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_people_v1_people_proto_goTypes,
+		DependencyIndexes: file_people_v1_people_proto_depIdxs,
+		MessageInfos:      file_people_v1_people_proto_msgTypes,
+	}.Build()
+	File_people_v1_people_proto = out.File
+	file_people_v1_people_proto_goTypes = nil
+	file_people_v1_people_proto_depIdxs = nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/app"
+	"github.com/Kirill-Pinyaev/people-api/internal/config"
+	"github.com/Kirill-Pinyaev/people-api/migrations"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const migrateUsage = "usage: people-api migrate [flags] up | down [n] | status | to <version>"
+
+// runMigrate handles the migrate subcommand:
+//
+//	up            apply every pending migration
+//	down [n]      roll back the last n migrations, default 1
+//	status        list migrations and the current version
+//	to <version>  migrate up or down to version
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	m, err := migrations.Open(cfg.DB.DSN)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = m.Up()
+	case args[0] == "down" && len(args) <= 2:
+		n := 1
+		if len(args) == 2 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+				return fmt.Errorf("down: %q is not a positive number", args[1])
+			}
+		}
+		err = m.Down(n)
+	case args[0] == "to" && len(args) == 2:
+		v, perr := strconv.ParseUint(args[1], 10, 64)
+		if perr != nil {
+			return fmt.Errorf("to: %q is not a version", args[1])
+		}
+		err = m.To(uint(v))
+	case args[0] == "status" && len(args) == 1:
+		return printStatus(m)
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(m)
+}
+
+func printStatus(m *migrations.Migrator) error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	state := ""
+	if dirty {
+		state = " (dirty: the last migration failed, fix the schema by hand)"
+	}
+	fmt.Printf("version %d of %d%s\n", version, migrations.Latest(), state)
+	for _, mg := range migrations.All() {
+		mark := "pending"
+		if mg.Version <= version {
+			mark = "applied"
+		}
+		fmt.Printf("  %03d  %-8s %s\n", mg.Version, mark, mg.Name)
+	}
+	return nil
+}
+
+func runSeed(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	pgxConfig, err := pgx.ParseConfig(cfg.DB.DSN)
+	if err != nil {
+		return err
+	}
+	db := stdlib.OpenDB(*pgxConfig)
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	seeded, err := migrations.Seed(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !seeded {
+		slog.Info("seed: database already has people, nothing to do")
+		return nil
+	}
+	slog.Info("seed: demo data loaded")
+	return nil
+}
+
+func migrateUp(dsn string) error {
+	m, err := migrations.Open(dsn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up()
+}
+
+// checkSchema refuses to serve against a database behind the embedded
+// migrations, where queries would fail in confusing ways.
+func checkSchema(a *app.App) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	version, dirty, err := a.Store.MigrationVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("schema version: %w; run `people-api migrate up` or start with -auto-migrate", err)
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty; fix it and run `people-api migrate up`", version)
+	}
+	if want := migrations.Latest(); uint(version) < want {
+		return fmt.Errorf("schema version %d is behind %d; run `people-api migrate up` or start with -auto-migrate", version, want)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Kirill-Pinyaev/people-api/pkg/client"
+)
+
+func emailsList(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	emails, err := e.client.ListEmails(ctx, id)
+	if err != nil {
+		return err
+	}
+	return render(e.output, emails, emailHeader, emailCells)
+}
+
+func emailsAdd(ctx context.Context, e *env, args []string) error {
+	fs := e.flags()
+	primary := fs.Bool("primary", false, "make it the primary address")
+	pos, err := e.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	em, err := e.client.AddEmail(ctx, id, pos[1], *primary)
+	if err != nil {
+		return err
+	}
+	return renderOne(e.output, em, emailHeader, emailCells)
+}
+
+func emailsDelete(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	emailID, err := parseID("email id", pos[1])
+	if err != nil {
+		return err
+	}
+	return e.client.DeleteEmail(ctx, id, emailID)
+}
+
+// --------- Friends
+
+func friendsList(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	friends, err := e.client.ListFriends(ctx, id)
+	if err != nil {
+		return err
+	}
+	return render(e.output, friends, personHeader, personCells)
+}
+
+func friendsAdd(ctx context.Context, e *env, args []string) error {
+	return friendsEdit(ctx, e, args, (*client.Client).AddFriend)
+}
+
+func friendsRemove(ctx context.Context, e *env, args []string) error {
+	return friendsEdit(ctx, e, args, (*client.Client).RemoveFriend)
+}
+
+func friendsEdit(ctx context.Context, e *env, args []string, call func(*client.Client, context.Context, int64, int64) error) error {
+	pos, err := e.parse(e.flags(), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	friendID, err := parseID("friend id", pos[1])
+	if err != nil {
+		return err
+	}
+	return call(e.client, ctx, id, friendID)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// optString is a string flag that stays nil unless given.
+type optString struct{ v *string }
+
+func (o *optString) String() string {
+	if o.v == nil {
+		return ""
+	}
+	return *o.v
+}
+
+func (o *optString) Set(s string) error {
+	o.v = &s
+	return nil
+}
+
+// optInt is an int flag that stays nil unless given.
+type optInt struct{ v *int }
+
+func (o *optInt) String() string {
+	if o.v == nil {
+		return ""
+	}
+	return strconv.Itoa(*o.v)
+}
+
+func (o *optInt) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	o.v = &n
+	return nil
+}
+
+// stringList collects a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
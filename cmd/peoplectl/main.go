@@ -0,0 +1,163 @@
+// Command peoplectl manages people, emails and friendships through the
+// People API.
+//
+//	peoplectl [flags] people list [-watch] | get ID | search SURNAME
+//	peoplectl [flags] people create -first NAME -last NAME [...] | update ID [...] | delete ID
+//	peoplectl [flags] emails list ID | add ID EMAIL [-primary] | delete ID EMAIL_ID
+//	peoplectl [flags] friends list ID | add ID FRIEND_ID | remove ID FRIEND_ID
+//	peoplectl config list | set NAME -url URL [-api-key KEY] | use NAME
+//
+// Flags go anywhere after the command: -profile, -url, -api-key and
+// -o table|json|csv.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Kirill-Pinyaev/people-api/pkg/client"
+)
+
+const usage = `usage: peoplectl <command> <action> [args] [flags]
+
+commands:
+  people   list [-watch] | get ID | search SURNAME | create | update ID | delete ID
+  emails   list ID | add ID EMAIL [-primary] | delete ID EMAIL_ID
+  friends  list ID | add ID FRIEND_ID | remove ID FRIEND_ID
+  config   list | set NAME -url URL [-api-key KEY] | use NAME
+
+flags:
+  -profile NAME   profile from the config file (env PEOPLECTL_PROFILE)
+  -url URL        API base URL (env PEOPLE_API_URL)
+  -api-key KEY    API key or JWT (env PEOPLE_API_KEY)
+  -o FORMAT       table, json or csv (default table)
+`
+
+// command is one "<command> <action>" pair.
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]map[string]command{
+	"people": {
+		"list":   peopleList,
+		"get":    peopleGet,
+		"search": peopleSearch,
+		"create": peopleCreate,
+		"update": peopleUpdate,
+		"delete": peopleDelete,
+	},
+	"emails": {
+		"list":   emailsList,
+		"add":    emailsAdd,
+		"delete": emailsDelete,
+	},
+	"friends": {
+		"list":   friendsList,
+		"add":    friendsAdd,
+		"remove": friendsRemove,
+	},
+	"config": {
+		"list": configList,
+		"set":  configSet,
+		"use":  configUse,
+	},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "peoplectl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+	cmd, ok := commands[args[0]][args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0]+" "+args[1])
+	}
+	return cmd(ctx, &env{name: args[0] + " " + args[1]}, args[2:])
+}
+
+// env carries the flags every command accepts and the resolved client.
+type env struct {
+	name    string
+	profile string
+	url     string
+	apiKey  string
+	output  string
+	client  *client.Client
+}
+
+// flags returns a flag set with the common flags registered.
+func (e *env) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("peoplectl "+e.name, flag.ContinueOnError)
+	fs.StringVar(&e.profile, "profile", os.Getenv("PEOPLECTL_PROFILE"), "config profile")
+	fs.StringVar(&e.url, "url", os.Getenv("PEOPLE_API_URL"), "API base URL")
+	fs.StringVar(&e.apiKey, "api-key", os.Getenv("PEOPLE_API_KEY"), "API key or JWT")
+	fs.StringVar(&e.output, "o", "table", "output format: table, json or csv")
+	return fs
+}
+
+// parse parses flags interleaved with exactly n positional arguments and
+// sets up the client.
+func (e *env) parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(pos) != n {
+		return nil, fmt.Errorf("%s: want %d argument(s), got %d", e.name, n, len(pos))
+	}
+	switch e.output {
+	case "table", "json", "csv":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", e.output)
+	}
+	if strings.HasPrefix(e.name, "config ") {
+		return pos, nil
+	}
+
+	p, err := resolveProfile(e.profile)
+	if err != nil {
+		return nil, err
+	}
+	if e.url == "" {
+		e.url = p.URL
+	}
+	if e.apiKey == "" {
+		e.apiKey = p.APIKey
+	}
+	e.client = client.New(e.url, e.apiKey)
+	return pos, nil
+}
+
+func parseID(what, s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", what, s)
+	}
+	return n, nil
+}
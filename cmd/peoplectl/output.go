@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Kirill-Pinyaev/people-api/pkg/client"
+)
+
+// render prints rows as an aligned table, CSV with a header line, or a JSON
+// array of the values themselves.
+func render[T any](format string, rows []T, header []string, cells func(T) []string) error {
+	switch format {
+	case "json":
+		if rows == nil {
+			rows = []T{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(header)
+		for _, r := range rows {
+			_ = w.Write(cells(r))
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		writeTableRow(w, header)
+		for _, r := range rows {
+			writeTableRow(w, cells(r))
+		}
+		return w.Flush()
+	}
+}
+
+func writeTableRow(w *tabwriter.Writer, cells []string) {
+	for i, c := range cells {
+		if i > 0 {
+			w.Write([]byte{'\t'})
+		}
+		// Tabs and newlines inside a value would break the columns.
+		w.Write([]byte(strings.NewReplacer("\t", " ", "\n", " ").Replace(c)))
+	}
+	w.Write([]byte{'\n'})
+}
+
+func writeFixedRow(widths []int, cells []string) error {
+	var b strings.Builder
+	for i, c := range cells {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		fmt.Fprintf(&b, "%-*s", widths[i], c)
+	}
+	_, err := fmt.Println(strings.TrimRight(b.String(), " "))
+	return err
+}
+
+var personHeader = []string{"ID", "FIRST_NAME", "MIDDLE_NAME", "LAST_NAME", "GENDER", "NATIONALITY", "AGE", "EMAILS", "FRIENDS"}
+
+func personCells(p client.Person) []string {
+	emails := make([]string, len(p.Emails))
+	for i, e := range p.Emails {
+		emails[i] = e.Email
+	}
+	return []string{
+		strconv.FormatInt(p.ID, 10),
+		p.FirstName,
+		str(p.MiddleName),
+		p.LastName,
+		str(p.Gender),
+		str(p.Nationality),
+		intStr(p.Age),
+		strings.Join(emails, ","),
+		strconv.Itoa(p.FriendsCount),
+	}
+}
+
+var emailHeader = []string{"ID", "PERSON_ID", "EMAIL", "PRIMARY"}
+
+func emailCells(e client.Email) []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		strconv.FormatInt(e.PersonID, 10),
+		e.Email,
+		strconv.FormatBool(e.IsPrimary),
+	}
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intStr(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// renderOne prints a single value; JSON output is the object, not an array.
+func renderOne[T any](format string, v T, header []string, cells func(T) []string) error {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	return render(format, []T{v}, header, cells)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Kirill-Pinyaev/people-api/pkg/client"
+)
+
+func peopleList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags()
+	watch := fs.Bool("watch", false, "after listing, print person changes as they happen")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	people, err := e.client.ListPeople(ctx)
+	if err != nil {
+		return err
+	}
+	if err := render(e.output, people, personHeader, personCells); err != nil {
+		return err
+	}
+	if !*watch {
+		return nil
+	}
+	return watchPeople(ctx, e)
+}
+
+// watchPeople prints every person event from now on, one row or JSON line
+// each, until interrupted. Every person event carries the person.
+func watchPeople(ctx context.Context, e *env) error {
+	opts := client.WatchOptions{Types: []string{"person.created", "person.updated", "person.deleted"}}
+	header := append([]string{"EVENT"}, personHeader...)
+
+	var emit func(client.Event, client.Person) error
+	switch e.output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		emit = func(ev client.Event, _ client.Person) error { return enc.Encode(ev) }
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(header)
+		emit = func(ev client.Event, p client.Person) error {
+			_ = w.Write(append([]string{ev.Type}, personCells(p)...))
+			w.Flush()
+			return w.Error()
+		}
+	default:
+		// Rows print as they arrive, so columns are sized from the header
+		// rather than aligned over the whole output.
+		widths := make([]int, len(header))
+		for i, h := range header {
+			widths[i] = max(len(h), 8)
+		}
+		widths[0] = len("person.updated")
+		writeFixedRow(widths, header)
+		emit = func(ev client.Event, p client.Person) error {
+			return writeFixedRow(widths, append([]string{ev.Type}, personCells(p)...))
+		}
+	}
+
+	err := e.client.Watch(ctx, opts, func(ev client.Event) error {
+		var p client.Person
+		if err := json.Unmarshal(ev.Data, &p); err != nil {
+			return fmt.Errorf("event %d: %w", ev.ID, err)
+		}
+		return emit(ev, p)
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func peopleGet(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	p, err := e.client.GetPerson(ctx, id)
+	if err != nil {
+		return err
+	}
+	return renderOne(e.output, p, personHeader, personCells)
+}
+
+func peopleSearch(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	people, err := e.client.SearchBySurname(ctx, pos[0])
+	if errors.Is(err, client.ErrNotFound) {
+		people, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	return render(e.output, people, personHeader, personCells)
+}
+
+// personFlags registers the editable fields; only flags given on the
+// command line end up set.
+type personFlags struct {
+	first, middle, last, gender, nationality optString
+	age                                      optInt
+}
+
+func (pf *personFlags) register(fs *flag.FlagSet) {
+	fs.Var(&pf.first, "first", "first name")
+	fs.Var(&pf.middle, "middle", "middle name")
+	fs.Var(&pf.last, "last", "last name")
+	fs.Var(&pf.gender, "gender", "gender")
+	fs.Var(&pf.nationality, "nationality", "nationality, ISO country code")
+	fs.Var(&pf.age, "age", "age")
+}
+
+func peopleCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags()
+	var pf personFlags
+	pf.register(fs)
+	var emails stringList
+	fs.Var(&emails, "email", "email address, repeatable; the first one is primary")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if pf.first.v == nil || pf.last.v == nil {
+		return errors.New("people create: -first and -last are required")
+	}
+
+	req := client.CreatePersonRequest{
+		FirstName:   *pf.first.v,
+		MiddleName:  pf.middle.v,
+		LastName:    *pf.last.v,
+		Gender:      pf.gender.v,
+		Nationality: pf.nationality.v,
+		Age:         pf.age.v,
+	}
+	for i, em := range emails {
+		req.Emails = append(req.Emails, struct {
+			Email     string `json:"email"`
+			IsPrimary bool   `json:"is_primary"`
+		}{em, i == 0})
+	}
+	p, err := e.client.CreatePerson(ctx, req)
+	if err != nil {
+		return err
+	}
+	return renderOne(e.output, p, personHeader, personCells)
+}
+
+func peopleUpdate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags()
+	var pf personFlags
+	pf.register(fs)
+	pos, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	p, err := e.client.UpdatePerson(ctx, id, client.UpdatePersonRequest{
+		FirstName:   pf.first.v,
+		MiddleName:  pf.middle.v,
+		LastName:    pf.last.v,
+		Gender:      pf.gender.v,
+		Nationality: pf.nationality.v,
+		Age:         pf.age.v,
+	})
+	if err != nil {
+		return err
+	}
+	return renderOne(e.output, p, personHeader, personCells)
+}
+
+func peopleDelete(ctx context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("id", pos[0])
+	if err != nil {
+		return err
+	}
+	return e.client.DeletePerson(ctx, id)
+}
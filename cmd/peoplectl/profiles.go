@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultURL = "http://localhost:8080"
+
+// profileFile is the peoplectl config, $PEOPLECTL_CONFIG or
+// <user config dir>/peoplectl/config.yaml:
+//
+//	current: prod
+//	profiles:
+//	  prod:
+//	    url: https://people.example.com
+//	    api_key: pk_...
+type profileFile struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]profile `yaml:"profiles,omitempty"`
+}
+
+type profile struct {
+	URL    string `yaml:"url"`
+	APIKey string `yaml:"api_key,omitempty"`
+}
+
+func configPath() (string, error) {
+	if p := os.Getenv("PEOPLECTL_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "peoplectl", "config.yaml"), nil
+}
+
+// loadProfiles returns an empty file when none exists yet.
+func loadProfiles() (*profileFile, string, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	pf := &profileFile{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pf, path, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := yaml.Unmarshal(b, pf); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return pf, path, nil
+}
+
+func (pf *profileFile) save(path string) error {
+	b, err := yaml.Marshal(pf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// The file holds API keys.
+	return os.WriteFile(path, b, 0o600)
+}
+
+// resolveProfile returns the named profile, the current one when name is
+// empty, or the local default when there is no config at all.
+func resolveProfile(name string) (profile, error) {
+	pf, path, err := loadProfiles()
+	if err != nil {
+		return profile{}, err
+	}
+	if name == "" {
+		name = pf.Current
+	}
+	if name == "" {
+		return profile{URL: defaultURL}, nil
+	}
+	p, ok := pf.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("no profile %q in %s", name, path)
+	}
+	return p, nil
+}
+
+// --------- config commands
+
+func configList(_ context.Context, e *env, args []string) error {
+	if _, err := e.parse(e.flags(), args, 0); err != nil {
+		return err
+	}
+	pf, _, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(pf.Profiles))
+	for n := range pf.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	type row struct {
+		Name    string `json:"name"`
+		URL     string `json:"url"`
+		Current bool   `json:"current"`
+	}
+	var rows []row
+	for _, n := range names {
+		rows = append(rows, row{n, pf.Profiles[n].URL, n == pf.Current})
+	}
+	return render(e.output, rows, []string{"NAME", "URL", "CURRENT"}, func(r row) []string {
+		cur := ""
+		if r.Current {
+			cur = "*"
+		}
+		return []string{r.Name, r.URL, cur}
+	})
+}
+
+func configSet(_ context.Context, e *env, args []string) error {
+	fs := e.flags()
+	pos, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if e.url == "" {
+		return errors.New("config set: -url is required")
+	}
+	pf, path, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	if pf.Profiles == nil {
+		pf.Profiles = map[string]profile{}
+	}
+	pf.Profiles[pos[0]] = profile{URL: e.url, APIKey: e.apiKey}
+	if pf.Current == "" {
+		pf.Current = pos[0]
+	}
+	return pf.save(path)
+}
+
+func configUse(_ context.Context, e *env, args []string) error {
+	pos, err := e.parse(e.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	pf, path, err := loadProfiles()
+	if err != nil {
+		return err
+	}
+	if _, ok := pf.Profiles[pos[0]]; !ok {
+		return fmt.Errorf("no profile %q in %s", pos[0], path)
+	}
+	pf.Current = pos[0]
+	return pf.save(path)
+}
@@ -2,24 +2,55 @@ package app
 
 import (
 	"database/sql"
+	"log/slog"
 	"net/http"
+	"sync/atomic"
 
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/changes"
+	"github.com/Kirill-Pinyaev/people-api/internal/config"
 	"github.com/Kirill-Pinyaev/people-api/internal/external/demographics"
+	"github.com/Kirill-Pinyaev/people-api/internal/gql"
 	"github.com/Kirill-Pinyaev/people-api/internal/store"
 )
 
 type App struct {
+	Config       *config.Config
 	DB           *sql.DB
+	Logger       *slog.Logger
 	HTTPClient   *http.Client
 	Demographics *demographics.Service
 	Store        *store.Store
+	APIKeys      *auth.APIKeys
+	Auth         auth.Chain
+	Changes      *changes.Hub
+	GraphQL      *gql.Schema
+
+	// Draining is set on shutdown so /readyz takes the replica out of
+	// rotation while in-flight requests finish.
+	Draining atomic.Bool
 }
 
-func New(db *sql.DB, client *http.Client) *App {
+func New(cfg *config.Config, db *sql.DB) *App {
+	client := &http.Client{Timeout: cfg.Outbound.Timeout}
+	st := store.New(db)
+	st.LimitGraphExports(cfg.DB.MaxGraphExports)
+	apiKeys := auth.NewAPIKeys(st)
+	apiKeys.BootstrapKey = cfg.Auth.BootstrapKey
+	demo := demographics.NewService(client, cfg.DemographicsConfig())
+	schema := gql.New(st, demo)
+	schema.MaxDepth = cfg.GraphQL.MaxDepth
+	schema.MaxComplexity = cfg.GraphQL.MaxComplexity
 	return &App{
+		Config:       cfg,
 		DB:           db,
+		Logger:       slog.Default(),
 		HTTPClient:   client,
-		Demographics: demographics.NewService(client),
-		Store:        store.New(db),
+		Demographics: demo,
+		Store:        st,
+		APIKeys:      apiKeys,
+		Auth:         auth.Chain{apiKeys},
+		Changes:      changes.NewHub(st, db),
+		GraphQL:      schema,
 	}
 }
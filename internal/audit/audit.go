@@ -0,0 +1,103 @@
+// Package audit holds the vocabulary and helpers for audit_events rows,
+// which the store writes in the same transaction as each mutation.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	EntityPerson     = "person"
+	EntityEmail      = "email"
+	EntityFriendship = "friendship"
+	EntityBlock      = "block"
+	EntityRelation   = "relation"
+	EntityAPIKey     = "api_key"
+	EntityWebhook    = "webhook"
+
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// SystemActor is recorded for writes made outside an authenticated request.
+const SystemActor = "system"
+
+func Actor(ctx context.Context) string {
+	if s := auth.Subject(ctx); s != "" {
+		return s
+	}
+	return SystemActor
+}
+
+func RequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
+// PairID is the entity id for edge entities such as friendships and blocks.
+func PairID(a, b int64) string {
+	return fmt.Sprintf("%d:%d", a, b)
+}
+
+type change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Diff renders the fields that differ between two JSON-serialisable
+// snapshots as {"field": {"before": ..., "after": ...}}. A nil before or
+// after (create, delete) reports every field of the other side.
+func Diff(before, after any) (json.RawMessage, error) {
+	b, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]change)
+	for k, bv := range b {
+		av, ok := a[k]
+		if !ok || string(av) != string(bv) {
+			out[k] = change{Before: bv, After: rawOrNil(av, ok)}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			out[k] = change{Before: nil, After: av}
+		}
+	}
+	return json.Marshal(out)
+}
+
+func flatten(v any) (map[string]json.RawMessage, error) {
+	out := map[string]json.RawMessage{}
+	if v == nil {
+		return out, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if string(raw) == "null" {
+		return out, nil
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func rawOrNil(v json.RawMessage, ok bool) any {
+	if !ok {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+// API keys look like "pk_<prefix>_<secret>". The prefix is stored in clear
+// for lookup; only the SHA-256 of the whole key is kept.
+const keyPrefix = "pk_"
+
+type KeyStore interface {
+	APIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int64) error
+}
+
+type APIKeys struct {
+	Keys KeyStore
+	// BootstrapKey, when set, is accepted as an admin key without a database
+	// row so the first real keys can be created.
+	BootstrapKey string
+}
+
+func NewAPIKeys(keys KeyStore) *APIKeys {
+	return &APIKeys{Keys: keys}
+}
+
+func (a *APIKeys) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if a.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.BootstrapKey)) == 1 {
+		return &Principal{Subject: "apikey:bootstrap", Scopes: []string{ScopeAdmin}}, nil
+	}
+
+	prefix, ok := ParseKey(token)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+	k, err := a.Keys.APIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if k.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(HashKey(token)), []byte(k.KeyHash)) != 1 {
+		return nil, ErrInvalidToken
+	}
+	if err := a.Keys.TouchAPIKey(ctx, k.ID); err != nil {
+		return nil, err
+	}
+	return &Principal{Subject: "apikey:" + strconv.FormatInt(k.ID, 10), Scopes: k.Scopes}, nil
+}
+
+// GenerateKey returns a fresh plaintext key and its lookup prefix.
+func GenerateKey() (key, prefix string, err error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(b[:4])
+	return keyPrefix + prefix + "_" + hex.EncodeToString(b[4:]), prefix, nil
+}
+
+// ParseKey extracts the lookup prefix from a plaintext key.
+func ParseKey(key string) (prefix string, ok bool) {
+	rest, ok := strings.CutPrefix(key, keyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+// Package auth authenticates bearer credentials on /v1 and enforces the
+// scopes each route requires.
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/logging"
+)
+
+const (
+	ScopePeopleRead   = "people:read"
+	ScopePeopleWrite  = "people:write"
+	ScopeEmailsWrite  = "emails:write"
+	ScopeFriendsWrite = "friends:write"
+	ScopeAdmin        = "admin"
+)
+
+var AllScopes = []string{ScopePeopleRead, ScopePeopleWrite, ScopeEmailsWrite, ScopeFriendsWrite, ScopeAdmin}
+
+func ValidScope(s string) bool {
+	return slices.Contains(AllScopes, s)
+}
+
+var (
+	// ErrNoCredentials means the authenticator does not recognise the token
+	// format; the next authenticator in a Chain gets a go.
+	ErrNoCredentials = errors.New("no credentials")
+	ErrInvalidToken  = errors.New("invalid token")
+)
+
+type Principal struct {
+	// Subject identifies the caller for auditing, e.g. "apikey:3".
+	Subject string
+	Scopes  []string
+}
+
+// Has reports whether p carries scope; admin implies every scope.
+func (p *Principal) Has(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// Chain tries each authenticator in turn until one recognises the token.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(ctx, token)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrInvalidToken
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(*Principal)
+	return p, ok
+}
+
+// Subject returns the authenticated subject in ctx, or "" if there is none.
+func Subject(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// Middleware requires a valid "Authorization: Bearer" credential and stores
+// the resulting Principal in the request context.
+func Middleware(a Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="people-api"`)
+				httputil.Error(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+			p, err := a.Authenticate(r.Context(), token)
+			if errors.Is(err, ErrInvalidToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="people-api", error="invalid_token"`)
+				httputil.Error(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			// Anything else, such as the key store being down, says nothing
+			// about the credential, and the details stay out of the response
+			// to an unauthenticated caller.
+			if err != nil {
+				slog.ErrorContext(r.Context(), "authenticate", "err", err)
+				httputil.Error(w, http.StatusInternalServerError, "authentication failed")
+				return
+			}
+			logging.Set(r.Context(), slog.String("subject", p.Subject))
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// Require rejects requests whose principal lacks scope.
+func Require(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := FromContext(r.Context())
+			if !ok || !p.Has(scope) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="people-api", error="insufficient_scope", scope="`+scope+`"`)
+				httputil.Error(w, http.StatusForbidden, "scope %s required", scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minRefetchInterval throttles refetches triggered by unknown key ids so a
+// stream of forged tokens cannot hammer the identity provider.
+const minRefetchInterval = 30 * time.Second
+
+// JWKS is a cached JSON Web Key Set. Remote sets are refetched every refresh
+// interval and whenever a token names a key id we have not seen, which is
+// how provider key rotation gets picked up. Static sets never change.
+type JWKS struct {
+	url          string
+	client       *http.Client
+	refreshEvery time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	fetchErr    error
+	// fetching is closed when the fetch in flight, if any, finishes.
+	fetching chan struct{}
+}
+
+func NewRemoteJWKS(url string, client *http.Client, refresh time.Duration) *JWKS {
+	return &JWKS{url: url, client: client, refreshEvery: refresh}
+}
+
+// NewStaticJWKS loads a key set from a local file, for tests and setups
+// without an identity provider.
+func NewStaticJWKS(path string) (*JWKS, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &JWKS{keys: keys, fetchedAt: time.Now()}, nil
+}
+
+// Key returns the key for kid. The lock is never held across a fetch: a
+// stale set keeps serving the keys it has while one refresh runs in the
+// background, and only callers the cache cannot serve wait for it.
+func (k *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if k.url != "" {
+		if err := k.refresh(ctx, kid); err != nil {
+			return nil, err
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, nil
+		}
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// refresh starts a fetch when the set is stale or does not know kid, at most
+// once per minRefetchInterval, and waits for it only when kid cannot be
+// served from the cache.
+func (k *JWKS) refresh(ctx context.Context, kid string) error {
+	k.mu.Lock()
+	_, known := k.keys[kid]
+	known = known || (kid == "" && len(k.keys) == 1)
+	stale := k.keys == nil || time.Since(k.fetchedAt) > k.refreshEvery
+	due := (stale || !known) && time.Since(k.attemptedAt) > minRefetchInterval
+	if due && k.fetching == nil {
+		k.fetching = make(chan struct{})
+		k.attemptedAt = time.Now()
+		// The fetch is shared, so it must outlive the caller that started it.
+		go k.fetch(context.WithoutCancel(ctx), k.fetching)
+	}
+	done := k.fetching
+	k.mu.Unlock()
+
+	if known || done == nil {
+		return k.keysOrErr()
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return k.keysOrErr()
+}
+
+// keysOrErr returns the last fetch error while there are no keys at all.
+func (k *JWKS) keysOrErr() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		return k.fetchErr
+	}
+	return nil
+}
+
+func (k *JWKS) fetch(ctx context.Context, done chan struct{}) {
+	keys, err := k.get(ctx)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err == nil {
+		k.keys = keys
+		k.fetchedAt = time.Now()
+	}
+	k.fetchErr = err
+	k.fetching = nil
+	close(done)
+}
+
+func (k *JWKS) get(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	return keys, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func parseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+	out := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			out[k.Kid] = key
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no usable signing keys")
+	}
+	return out, nil
+}
+
+// publicKey returns nil for key types we do not verify with.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64int(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64int(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64int(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64int(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func b64int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const jwtLeeway = 30 * time.Second
+
+// JWT authenticates RS256/ES256 bearer tokens issued by the gateway. Scopes
+// come from the "scope" (space-separated) or "scp" (list) claim; anything
+// the API does not know is dropped.
+type JWT struct {
+	keys     *JWKS
+	issuer   string
+	audience string
+}
+
+func NewJWT(keys *JWKS, issuer, audience string) (*JWT, error) {
+	if issuer == "" || audience == "" {
+		return nil, errors.New("jwt: issuer and audience are required")
+	}
+	return &JWT{keys: keys, issuer: issuer, audience: audience}, nil
+}
+
+func (j *JWT) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if strings.Count(token, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return j.keys.Key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(j.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	return &Principal{Subject: "jwt:" + sub, Scopes: claimScopes(claims)}, nil
+}
+
+func claimScopes(c jwt.MapClaims) []string {
+	var raw []string
+	if v, ok := c["scope"].(string); ok {
+		raw = append(raw, strings.Fields(v)...)
+	}
+	switch v := c["scp"].(type) {
+	case string:
+		raw = append(raw, strings.Fields(v)...)
+	case []any:
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				raw = append(raw, str)
+			}
+		}
+	}
+
+	var out []string
+	for _, s := range raw {
+		if ValidScope(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
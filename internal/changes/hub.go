@@ -0,0 +1,259 @@
+// Package changes fans outbox events out to long-lived client connections.
+// Every replica LISTENs for the outbox_events notification, so clients see
+// changes no matter which replica made them.
+package changes
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	channel = "outbox_events"
+
+	// subscriberBuffer is how far a client may fall behind before it is
+	// dropped and has to resume with Last-Event-ID.
+	subscriberBuffer = 256
+	backlogPage      = 500
+	reconnectDelay   = 2 * time.Second
+	// pollInterval bounds how long events held back behind a transaction
+	// that wrote none of its own wait after it ends, since nothing notifies
+	// then.
+	pollInterval = time.Second
+)
+
+var (
+	// ErrLagged ends a Follow whose client could not keep up.
+	ErrLagged = errors.New("subscriber fell behind")
+	// ErrClosed ends every Follow once the hub is closed for shutdown.
+	ErrClosed = errors.New("hub closed")
+)
+
+type Store interface {
+	OutboxEventsAfter(ctx context.Context, after int64, limit int) ([]models.OutboxEvent, error)
+	OutboxEventTxID(ctx context.Context, id int64) (int64, error)
+	LatestOutboxEventID(ctx context.Context) (int64, error)
+}
+
+type subscriber struct {
+	types []string
+	c     chan models.OutboxEvent
+	// err says why c was closed; it is set before the close.
+	err error
+}
+
+func (s *subscriber) wants(t string) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, t)
+}
+
+type Hub struct {
+	st Store
+	db *sql.DB
+
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	closed bool
+	// last is the last event published, where reading the feed resumes.
+	last int64
+}
+
+func NewHub(st Store, db *sql.DB) *Hub {
+	return &Hub{st: st, db: db, subs: make(map[*subscriber]struct{})}
+}
+
+// Run listens for new events until ctx is cancelled, reconnecting as needed.
+func (h *Hub) Run(ctx context.Context) {
+	for {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "changes: listen", "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context) error {
+	if h.last == 0 {
+		last, err := h.st.LatestOutboxEventID(ctx)
+		if err != nil {
+			return err
+		}
+		h.last = last
+	}
+
+	conn, err := h.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(dc any) error {
+		pc := dc.(*stdlib.Conn).Conn()
+		err := func() error {
+			if _, err := pc.Exec(ctx, "LISTEN "+channel); err != nil {
+				return err
+			}
+			// Read the feed on start, on every notification and every
+			// pollInterval, as held-back events are released without one.
+			for {
+				for {
+					evs, err := h.st.OutboxEventsAfter(ctx, h.last, backlogPage)
+					if err != nil {
+						return err
+					}
+					h.publish(evs)
+					if len(evs) < backlogPage {
+						break
+					}
+				}
+				waitCtx, cancel := context.WithTimeout(ctx, pollInterval)
+				_, err := pc.WaitForNotification(waitCtx)
+				cancel()
+				if err != nil && (ctx.Err() != nil || !pgconn.Timeout(err)) {
+					return err
+				}
+			}
+		}()
+		// Never hand a connection with an active LISTEN back to the pool.
+		return fmt.Errorf("%w: %w", driver.ErrBadConn, err)
+	})
+}
+
+// publish hands events to every interested subscriber without blocking;
+// subscribers whose buffer is full are dropped.
+func (h *Hub) publish(evs []models.OutboxEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range evs {
+		h.last = e.ID
+		for s := range h.subs {
+			if !s.wants(e.Type) {
+				continue
+			}
+			select {
+			case s.c <- e:
+			default:
+				h.drop(s, ErrLagged)
+			}
+		}
+	}
+}
+
+func (h *Hub) subscribe(types []string) *subscriber {
+	s := &subscriber{types: types, c: make(chan models.OutboxEvent, subscriberBuffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		s.err = ErrClosed
+		close(s.c)
+		return s
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+func (h *Hub) unsubscribe(s *subscriber) {
+	h.mu.Lock()
+	if _, ok := h.subs[s]; ok {
+		h.drop(s, nil)
+	}
+	h.mu.Unlock()
+}
+
+// drop removes s and closes its channel; h.mu must be held.
+func (h *Hub) drop(s *subscriber, err error) {
+	s.err = err
+	delete(h.subs, s)
+	close(s.c)
+}
+
+// Close ends every current and future Follow with ErrClosed, so that
+// streaming clients reconnect to another replica during shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		h.drop(s, ErrClosed)
+	}
+}
+
+// Follow passes events of the given types (all when empty) to send: first
+// those after the after id, read from the outbox, then live ones. Every
+// heartbeat without events, send is called with nil so the caller can keep
+// the connection alive. It returns when ctx is done, send fails, the client
+// falls behind (ErrLagged) or the hub is closed (ErrClosed). Events come
+// in feed order (see store.OutboxEventsAfter), which is not id order, but
+// resuming after the last id delivered misses nothing.
+func (h *Hub) Follow(ctx context.Context, after int64, types []string, heartbeat time.Duration, send func(*models.OutboxEvent) error) error {
+	// Subscribe before reading the backlog so nothing slips between the two.
+	s := h.subscribe(types)
+	defer h.unsubscribe(s)
+
+	// The hub may publish events the backlog, or another replica, already
+	// delivered; skip everything up to the last one sent.
+	afterTx := int64(-1)
+	if after > 0 {
+		var err error
+		if afterTx, err = h.st.OutboxEventTxID(ctx, after); err != nil {
+			return err
+		}
+		for {
+			evs, err := h.st.OutboxEventsAfter(ctx, after, backlogPage)
+			if err != nil {
+				return err
+			}
+			for i := range evs {
+				if s.wants(evs[i].Type) {
+					if err := send(&evs[i]); err != nil {
+						return err
+					}
+				}
+				after, afterTx = evs[i].ID, evs[i].TxID
+			}
+			if len(evs) < backlogPage {
+				break
+			}
+		}
+	}
+
+	t := time.NewTicker(heartbeat)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-s.c:
+			if !ok {
+				return s.err
+			}
+			if e.TxID < afterTx || e.TxID == afterTx && e.ID <= after {
+				continue
+			}
+			if err := send(&e); err != nil {
+				return err
+			}
+			t.Reset(heartbeat)
+		case <-t.C:
+			if err := send(nil); err != nil {
+				return err
+			}
+		}
+	}
+}
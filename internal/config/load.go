@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from defaults, the YAML file named by
+// -config or CONFIG_FILE, the environment and the flags in args, then
+// validates it. It returns the arguments left after the flags.
+func Load(args []string) (*Config, []string, error) {
+	c := Default()
+
+	fs := flag.NewFlagSet("people-api", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML config file (env CONFIG_FILE)")
+	type flagValue struct {
+		name  string
+		field reflect.Value
+		value string
+	}
+	var flagged []flagValue
+	walk(reflect.ValueOf(c).Elem(), "", func(path string, f reflect.StructField, v reflect.Value) {
+		usage := "config " + path
+		if env := f.Tag.Get("env"); env != "" {
+			usage += " (env " + env + ")"
+		}
+		name := path
+		if n := f.Tag.Get("flag"); n != "" {
+			name = n
+		}
+		record := func(s string) error {
+			flagged = append(flagged, flagValue{name, v, s})
+			return nil
+		}
+		if v.Kind() == reflect.Bool {
+			fs.BoolFunc(name, usage, record)
+		} else {
+			fs.Func(name, usage, record)
+		}
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(c, *file); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var errs []error
+	walk(reflect.ValueOf(c).Elem(), "", func(path string, f reflect.StructField, v reflect.Value) {
+		env := f.Tag.Get("env")
+		if env == "" {
+			return
+		}
+		if s, ok := os.LookupEnv(env); ok && s != "" {
+			if err := set(v, s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", env, err))
+			}
+		}
+	})
+	for _, fv := range flagged {
+		if err := set(fv.field, fv.value); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", fv.name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return c, fs.Args(), nil
+}
+
+func loadFile(c *Config, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field of the struct v with its dotted YAML
+// path.
+func walk(v reflect.Value, prefix string, fn func(path string, f reflect.StructField, v reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		if f.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), path+".", fn)
+			continue
+		}
+		fn(path, f, v.Field(i))
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set parses s into v. Lists are comma-separated.
+func set(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var out []string
+		for _, p := range strings.Split(s, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+		v.Set(reflect.ValueOf(out))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
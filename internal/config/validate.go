@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const redacted = "[REDACTED]"
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	positive := func(name string, d time.Duration) {
+		check(d > 0, "%s must be positive", name)
+	}
+
+	check(c.HTTP.Addr != "", "http.addr is required")
+	positive("http.request_timeout", c.HTTP.RequestTimeout)
+	positive("http.idempotency_ttl", c.HTTP.IdempotencyTTL)
+	for _, o := range c.HTTP.CORSOrigins {
+		check(o == "*" || absoluteURL(o), "http.cors_origins: %q is not an origin", o)
+	}
+	check(c.GRPC.Addr != "", "grpc.addr is required")
+
+	if _, err := pgx.ParseConfig(c.DB.DSN); err != nil {
+		// The parse error may quote the DSN, password included.
+		errs = append(errs, errors.New("db.dsn is not a valid connection string"))
+	}
+	check(c.DB.MaxOpenConns > 0, "db.max_open_conns must be positive")
+	check(c.DB.MaxIdleConns >= 0 && c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "db.max_idle_conns must be between 0 and db.max_open_conns")
+	check(c.DB.MaxGraphExports > 0 && c.DB.MaxGraphExports < c.DB.MaxOpenConns, "db.max_graph_exports must be positive and below db.max_open_conns")
+	positive("db.conn_max_lifetime", c.DB.ConnMaxLifetime)
+
+	check(slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(c.Log.Level)), "log.level must be debug, info, warn or error")
+	check(slices.Contains([]string{"json", "text"}, strings.ToLower(c.Log.Format)), "log.format must be json or text")
+
+	check(slices.Contains([]string{"", "none", "console", "otlp"}, strings.ToLower(c.Tracing.Exporter)), "tracing.exporter must be none, console or otlp")
+	check(slices.Contains([]string{"", "grpc", "http/protobuf"}, c.Tracing.OTLPProtocol), "tracing.otlp_protocol must be grpc or http/protobuf")
+	check(c.Tracing.OTLPEndpoint == "" || absoluteURL(c.Tracing.OTLPEndpoint), "tracing.otlp_endpoint must be an absolute URL")
+
+	check(c.Auth.JWT.JWKSURL == "" || absoluteURL(c.Auth.JWT.JWKSURL), "auth.jwt.jwks_url must be an absolute URL")
+	positive("auth.jwt.jwks_refresh", c.Auth.JWT.JWKSRefresh)
+
+	positive("outbound.timeout", c.Outbound.Timeout)
+
+	positive("demographics.timeout", c.Demographics.Timeout)
+	check(absoluteURL(c.Demographics.AgifyURL), "demographics.agify_url must be an absolute URL")
+	check(absoluteURL(c.Demographics.GenderizeURL), "demographics.genderize_url must be an absolute URL")
+	check(absoluteURL(c.Demographics.NationalizeURL), "demographics.nationalize_url must be an absolute URL")
+	check(c.Demographics.BreakerThreshold > 0, "demographics.breaker_threshold must be positive")
+	positive("demographics.breaker_cooldown", c.Demographics.BreakerCooldown)
+
+	positive("webhooks.timeout", c.Webhooks.Timeout)
+	positive("webhooks.poll_interval", c.Webhooks.PollInterval)
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+
+	check(c.GraphQL.MaxDepth > 0, "graphql.max_depth must be positive")
+	check(c.GraphQL.MaxComplexity > 0, "graphql.max_complexity must be positive")
+
+	check(c.Shutdown.Delay >= 0, "shutdown.delay must not be negative")
+	positive("shutdown.timeout", c.Shutdown.Timeout)
+
+	return errors.Join(errs...)
+}
+
+func absoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Redacted returns a copy with secrets masked: the bootstrap key and the
+// DSN password.
+func (c *Config) Redacted() *Config {
+	out := *c
+	if out.Auth.BootstrapKey != "" {
+		out.Auth.BootstrapKey = redacted
+	}
+	if u, err := url.Parse(out.DB.DSN); err == nil && u.Scheme != "" {
+		out.DB.DSN = u.Redacted()
+	} else if out.DB.DSN != "" {
+		// Keyword/value DSNs are not worth parsing just to print them.
+		out.DB.DSN = redacted
+	}
+	return &out
+}
+
+// LogValue logs the redacted configuration as nested groups.
+func (c *Config) LogValue() slog.Value {
+	return groupValue(reflect.ValueOf(c.Redacted()).Elem())
+}
+
+func groupValue(v reflect.Value) slog.Value {
+	t := v.Type()
+	var attrs []slog.Attr
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		f := v.Field(i)
+		switch {
+		case f.Kind() == reflect.Struct:
+			attrs = append(attrs, slog.Attr{Key: name, Value: groupValue(f)})
+		case f.Type() == durationType:
+			attrs = append(attrs, slog.String(name, time.Duration(f.Int()).String()))
+		case f.Kind() == reflect.Slice:
+			attrs = append(attrs, slog.String(name, strings.Join(f.Interface().([]string), ",")))
+		default:
+			attrs = append(attrs, slog.Any(name, f.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
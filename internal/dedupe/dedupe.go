@@ -0,0 +1,225 @@
+// Package dedupe scores pairs of people as likely duplicates. Names are
+// compared after transliterating Cyrillic to Latin, so "Иван Иванов" and
+// "Ivan Ivanov" compare equal.
+package dedupe
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+const (
+	weightLastName  = 0.45
+	weightFirstName = 0.35
+	weightAge       = 0.1
+	weightNation    = 0.1
+	bonusEmail      = 0.5
+	penaltyGender   = 0.2
+)
+
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Normalize lowercases, transliterates Cyrillic and drops everything that is
+// not a letter.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if t, ok := cyrillic[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SurnameKey is the blocking key FindPairs groups surnames by; the store
+// keeps it in people.dedupe_key.
+func SurnameKey(lastName string) string {
+	return surnameKey(Normalize(lastName))
+}
+
+func surnameKey(norm string) string {
+	if r := []rune(norm); len(r) > 3 {
+		return string(r[:3])
+	}
+	return norm
+}
+
+// Candidate is a person with the emails used for matching.
+type Candidate struct {
+	models.Person
+	norm struct{ first, last string }
+	mail map[string]bool
+}
+
+func NewCandidate(p models.Person) *Candidate {
+	c := &Candidate{Person: p, mail: make(map[string]bool, len(p.Emails))}
+	c.norm.first = Normalize(p.FirstName)
+	c.norm.last = Normalize(p.LastName)
+	for _, e := range p.Emails {
+		c.mail[strings.ToLower(strings.TrimSpace(e.Email))] = true
+	}
+	return c
+}
+
+// Score rates how likely a and b describe the same person, in [0, 1], and
+// lists what matched.
+func Score(a, b *Candidate) (float64, []string) {
+	var reasons []string
+	score := 0.0
+
+	last := JaroWinkler(a.norm.last, b.norm.last)
+	first := JaroWinkler(a.norm.first, b.norm.first)
+	score += weightLastName*last + weightFirstName*first
+	if last == 1 && first == 1 {
+		if a.FirstName == b.FirstName && a.LastName == b.LastName {
+			reasons = append(reasons, "same_name")
+		} else {
+			reasons = append(reasons, "same_name_transliterated")
+		}
+	} else if last >= 0.85 && first >= 0.85 {
+		reasons = append(reasons, "similar_name")
+	}
+
+	if a.Age != nil && b.Age != nil && *a.Age == *b.Age {
+		score += weightAge
+		reasons = append(reasons, "same_age")
+	}
+	if a.Nationality != nil && b.Nationality != nil && strings.EqualFold(*a.Nationality, *b.Nationality) {
+		score += weightNation
+		reasons = append(reasons, "same_nationality")
+	}
+	if a.Gender != nil && b.Gender != nil && !strings.EqualFold(*a.Gender, *b.Gender) {
+		score -= penaltyGender
+		reasons = append(reasons, "gender_mismatch")
+	}
+	for m := range a.mail {
+		if b.mail[m] {
+			score += bonusEmail
+			reasons = append(reasons, "shared_email")
+			break
+		}
+	}
+
+	return min(max(score, 0), 1), reasons
+}
+
+// Pair is a scored duplicate candidate; A has the lower id.
+type Pair struct {
+	A, B    *Candidate
+	Score   float64
+	Reasons []string
+}
+
+// FindPairs scores every pair that shares a blocking key (the first three
+// letters of the normalised surname, or an email) and returns those scoring
+// at least minScore, best first.
+func FindPairs(cs []*Candidate, minScore float64) []Pair {
+	blocks := make(map[string][]int)
+	for i, c := range cs {
+		key := "n:" + surnameKey(c.norm.last)
+		blocks[key] = append(blocks[key], i)
+		for m := range c.mail {
+			blocks["e:"+m] = append(blocks["e:"+m], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	var out []Pair
+	for _, idx := range blocks {
+		for x := 0; x < len(idx); x++ {
+			for y := x + 1; y < len(idx); y++ {
+				i, j := idx[x], idx[y]
+				if cs[i].ID > cs[j].ID {
+					i, j = j, i
+				}
+				if seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+				s, reasons := Score(cs[i], cs[j])
+				if s >= minScore {
+					out = append(out, Pair{A: cs[i], B: cs[j], Score: s, Reasons: reasons})
+				}
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].A.ID != out[j].A.ID {
+			return out[i].A.ID < out[j].A.ID
+		}
+		return out[i].B.ID < out[j].B.ID
+	})
+	return out
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings in [0, 1].
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+	ma := make([]bool, len(ra))
+	mb := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if mb[j] || ra[i] != rb[j] {
+				continue
+			}
+			ma[i], mb[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	k := 0
+	for i := range ra {
+		if !ma[i] {
+			continue
+		}
+		for !mb[k] {
+			k++
+		}
+		if ra[i] != rb[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for i := 0; i < min(4, len(ra), len(rb)) && ra[i] == rb[i]; i++ {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
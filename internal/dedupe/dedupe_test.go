@@ -0,0 +1,168 @@
+package dedupe
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Ivanov":        "ivanov",
+		"Иванов":        "ivanov",
+		"ЩУКИН":         "shchukin",
+		"Хрущёв":        "khrushchev",
+		"Мельников-Ким": "melnikovkim",
+		"Подъячев":      "podyachev",
+		"Їжак Євген":    "yizhakyevgen",
+		"O'Brien 2":     "obrien",
+		"":              "",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSurnameKey(t *testing.T) {
+	tests := map[string]string{
+		"Иванов":  "iva",
+		"Ivanova": "iva",
+		"Щукин":   "shc",
+		"Li":      "li",
+		"Öztürk":  "özt",
+	}
+	for in, want := range tests {
+		if got := SurnameKey(in); got != want {
+			t.Errorf("SurnameKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dwayne", "duane", 0.840},
+		{"dixon", "dicksonx", 0.813},
+		{"ivanov", "ivanov", 1},
+		{"", "", 1},
+		{"ivanov", "", 0},
+		{"abc", "xyz", 0},
+	}
+	for _, tt := range tests {
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+		if got, rev := JaroWinkler(tt.a, tt.b), JaroWinkler(tt.b, tt.a); got != rev {
+			t.Errorf("JaroWinkler(%q, %q) = %v but reversed %v", tt.a, tt.b, got, rev)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	age := func(n int) *int { return &n }
+	str := func(s string) *string { return &s }
+	person := func(id int64, first, last string) models.Person {
+		return models.Person{ID: id, FirstName: first, LastName: last}
+	}
+
+	tests := []struct {
+		name    string
+		a, b    models.Person
+		score   float64
+		reasons []string
+	}{
+		{
+			name:    "identical",
+			a:       person(1, "Ivan", "Ivanov"),
+			b:       person(2, "Ivan", "Ivanov"),
+			score:   0.8,
+			reasons: []string{"same_name"},
+		},
+		{
+			name:    "transliterated",
+			a:       person(1, "Ivan", "Ivanov"),
+			b:       person(2, "Иван", "Иванов"),
+			score:   0.8,
+			reasons: []string{"same_name_transliterated"},
+		},
+		{
+			name:    "same age and nationality",
+			a:       models.Person{ID: 1, FirstName: "Ivan", LastName: "Ivanov", Age: age(28), Nationality: str("RU")},
+			b:       models.Person{ID: 2, FirstName: "Ivan", LastName: "Ivanov", Age: age(28), Nationality: str("ru")},
+			score:   1,
+			reasons: []string{"same_name", "same_age", "same_nationality"},
+		},
+		{
+			name:    "gender mismatch",
+			a:       models.Person{ID: 1, FirstName: "Sasha", LastName: "Petrov", Gender: str("male")},
+			b:       models.Person{ID: 2, FirstName: "Sasha", LastName: "Petrov", Gender: str("female")},
+			score:   0.6,
+			reasons: []string{"same_name", "gender_mismatch"},
+		},
+		{
+			name: "shared email",
+			a: models.Person{ID: 1, FirstName: "John", LastName: "Smith",
+				Emails: []models.Email{{Email: "js@example.com"}}},
+			b: models.Person{ID: 2, FirstName: "Jon", LastName: "Smyth",
+				Emails: []models.Email{{Email: " JS@example.com"}}},
+			score:   1,
+			reasons: []string{"similar_name", "shared_email"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reasons := Score(NewCandidate(tt.a), NewCandidate(tt.b))
+			if math.Abs(got-tt.score) > 1e-9 {
+				t.Errorf("score = %.3f, want %.3f", got, tt.score)
+			}
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestFindPairs(t *testing.T) {
+	people := []models.Person{
+		{ID: 4, FirstName: "Ivan", LastName: "Ivanov"},
+		{ID: 1, FirstName: "Иван", LastName: "Иванов"},
+		{ID: 2, FirstName: "Ivan", LastName: "Ivanova"},
+		{ID: 3, FirstName: "Maria", LastName: "Garcia"},
+		// Different surname key, so only the shared email pairs them.
+		{ID: 5, FirstName: "Maria", LastName: "Smith", Emails: []models.Email{{Email: "m@example.com"}}},
+		{ID: 6, FirstName: "Maria", LastName: "Garcia-Smith", Emails: []models.Email{{Email: "M@example.com"}}},
+	}
+	cs := make([]*Candidate, len(people))
+	for i, p := range people {
+		cs[i] = NewCandidate(p)
+	}
+
+	pairs := FindPairs(cs, 0.75)
+	var got [][2]int64
+	for _, p := range pairs {
+		if p.A.ID >= p.B.ID {
+			t.Errorf("pair %d-%d: A must have the lower id", p.A.ID, p.B.ID)
+		}
+		got = append(got, [2]int64{p.A.ID, p.B.ID})
+	}
+	want := [][2]int64{{5, 6}, {1, 4}, {1, 2}, {2, 4}, {3, 6}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("pairs = %v, want %v", got, want)
+	}
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i].Score > pairs[i-1].Score {
+			t.Errorf("pairs not sorted by score: %v", pairs)
+		}
+	}
+
+	if pairs := FindPairs(cs, 1.01); len(pairs) != 0 {
+		t.Errorf("min score above 1 returned %d pairs", len(pairs))
+	}
+}
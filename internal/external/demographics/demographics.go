@@ -3,22 +3,98 @@ package demographics
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/metrics"
+	"github.com/Kirill-Pinyaev/people-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Providers queried by Infer.
+var Providers = []string{"agify", "genderize", "nationalize"}
+
+type Config struct {
+	// Timeout bounds one Infer call, i.e. all three provider requests.
+	Timeout        time.Duration
+	AgifyURL       string
+	GenderizeURL   string
+	NationalizeURL string
+	// A provider's circuit opens after BreakerThreshold consecutive
+	// failures and is retried after BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          3 * time.Second,
+		AgifyURL:         "https://api.agify.io/",
+		GenderizeURL:     "https://api.genderize.io/",
+		NationalizeURL:   "https://api.nationalize.io/",
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
 type Service struct {
-	client *http.Client
-	logger *log.Logger
+	client   *http.Client
+	logger   *slog.Logger
+	cfg      Config
+	urls     map[string]string
+	breakers map[string]*breaker
 }
 
-func NewService(c *http.Client) *Service {
-	return &Service{
+func NewService(c *http.Client, cfg Config) *Service {
+	s := &Service{
 		client: c,
-		logger: log.Default(),
+		logger: slog.Default(),
+		cfg:    cfg,
+		urls: map[string]string{
+			"agify":       cfg.AgifyURL,
+			"genderize":   cfg.GenderizeURL,
+			"nationalize": cfg.NationalizeURL,
+		},
+		breakers: make(map[string]*breaker, len(Providers)),
+	}
+	for _, p := range Providers {
+		s.breakers[p] = &breaker{threshold: cfg.BreakerThreshold, cooldown: cfg.BreakerCooldown}
+	}
+	return s
+}
+
+// Circuits reports each provider's circuit breaker state.
+func (s *Service) Circuits() map[string]string {
+	out := make(map[string]string, len(s.breakers))
+	for p, b := range s.breakers {
+		out[p] = b.state()
+	}
+	return out
+}
+
+// Complete fills in whichever of age, gender and nationality are nil from
+// Infer, calling the providers only if something is missing.
+func (s *Service) Complete(ctx context.Context, firstName string, age *int, gender, nationality *string) (*int, *string, *string) {
+	if age != nil && gender != nil && nationality != nil {
+		return age, gender, nationality
 	}
+	a, g, n := s.Infer(ctx, firstName)
+	if age == nil {
+		age = a
+	}
+	if gender == nil {
+		gender = g
+	}
+	if nationality == nil {
+		nationality = n
+	}
+	return age, gender, nationality
 }
 
 func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *string) {
@@ -35,7 +111,11 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 		} `json:"country"`
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer metrics.EnrichmentStarted()()
+	ctx, span := tracing.Tracer().Start(ctx, "demographics.Infer")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
 	defer cancel()
 
 	type result[T any] struct {
@@ -50,8 +130,15 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 		start := time.Now()
 		var out *int
 		var status int
-		url := fmt.Sprintf("https://api.agify.io/?name=%s", firstName)
+		if !s.breakers["agify"].allow() {
+			metrics.ObserveProvider("agify", metrics.OutcomeCircuitOpen, 0)
+			ageCh <- result[*int]{}
+			return
+		}
+		ctx := s.startSpan(ctx, "agify")
+		url := s.urls["agify"] + "?name=" + firstName
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		tracing.Inject(ctx, req.Header)
 		resp, err := s.client.Do(req)
 		if resp != nil {
 			status = resp.StatusCode
@@ -63,8 +150,7 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 				out = ar.Age
 			}
 		}
-		s.logger.Printf("demographics provider=agify name=%q status=%d dur=%s err=%v",
-			firstName, status, time.Since(start), err)
+		s.observe(ctx, "agify", firstName, status, time.Since(start), err)
 		ageCh <- result[*int]{v: out, err: err}
 	}()
 
@@ -72,8 +158,15 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 		start := time.Now()
 		var status int
 		var out *string
-		url := fmt.Sprintf("https://api.genderize.io/?name=%s", firstName)
+		if !s.breakers["genderize"].allow() {
+			metrics.ObserveProvider("genderize", metrics.OutcomeCircuitOpen, 0)
+			genCh <- result[*string]{}
+			return
+		}
+		ctx := s.startSpan(ctx, "genderize")
+		url := s.urls["genderize"] + "?name=" + firstName
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		tracing.Inject(ctx, req.Header)
 		resp, err := s.client.Do(req)
 		if resp != nil {
 			status = resp.StatusCode
@@ -85,8 +178,7 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 				out = gr.Gender
 			}
 		}
-		s.logger.Printf("demographics provider=genderize name=%q status=%d dur=%s err=%v",
-			firstName, status, time.Since(start), err)
+		s.observe(ctx, "genderize", firstName, status, time.Since(start), err)
 		genCh <- result[*string]{v: out, err: err}
 	}()
 
@@ -94,8 +186,15 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 		start := time.Now()
 		var status int
 		var out *string
-		url := fmt.Sprintf("https://api.nationalize.io/?name=%s", firstName)
+		if !s.breakers["nationalize"].allow() {
+			metrics.ObserveProvider("nationalize", metrics.OutcomeCircuitOpen, 0)
+			natCh <- result[*string]{}
+			return
+		}
+		ctx := s.startSpan(ctx, "nationalize")
+		url := s.urls["nationalize"] + "?name=" + firstName
 		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		tracing.Inject(ctx, req.Header)
 		resp, err := s.client.Do(req)
 		if resp != nil {
 			status = resp.StatusCode
@@ -113,8 +212,7 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 				out = &best.CountryID
 			}
 		}
-		s.logger.Printf("demographics provider=nationalize name=%q status=%d dur=%s err=%v",
-			firstName, status, time.Since(start), err)
+		s.observe(ctx, "nationalize", firstName, status, time.Since(start), err)
 		natCh <- result[*string]{v: out, err: err}
 	}()
 
@@ -123,3 +221,64 @@ func (s *Service) Infer(ctx context.Context, firstName string) (*int, *string, *
 	nationality := (<-natCh).v
 	return age, gender, nationality
 }
+
+// startSpan starts the client span for one provider request. The URL is not
+// recorded because its query string is the person's name.
+func (s *Service) startSpan(ctx context.Context, provider string) context.Context {
+	var host string
+	if u, err := url.Parse(s.urls[provider]); err == nil {
+		host = u.Host
+	}
+	ctx, _ = tracing.Tracer().Start(ctx, "demographics "+provider,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("demographics.provider", provider),
+			semconv.ServerAddress(host),
+		),
+	)
+	return ctx
+}
+
+// observe logs, counts and ends the span of one provider request. The name
+// goes under the "name" key so the logger can redact it.
+func (s *Service) observe(ctx context.Context, provider, name string, status int, dur time.Duration, err error) {
+	level, outcome := slog.LevelInfo, metrics.OutcomeOK
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		level, outcome = slog.LevelWarn, metrics.OutcomeTimeout
+	case err != nil:
+		level, outcome = slog.LevelWarn, metrics.OutcomeError
+	case status != http.StatusOK:
+		level, outcome = slog.LevelWarn, metrics.OutcomeBadStatus
+	}
+	metrics.ObserveProvider(provider, outcome, dur)
+	// A caller that went away says nothing about the provider.
+	if !errors.Is(err, context.Canceled) {
+		s.breakers[provider].record(outcome == metrics.OutcomeOK)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if status != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	}
+	if outcome != metrics.OutcomeOK {
+		span.SetStatus(codes.Error, outcome)
+	}
+	defer span.End()
+
+	attrs := []slog.Attr{
+		slog.String("provider", provider),
+		slog.String("name", name),
+		slog.Int("status", status),
+		slog.Duration("latency", dur),
+	}
+	if err != nil {
+		// *url.Error repeats the request URL, which contains the name.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		attrs = append(attrs, slog.String("err", err.Error()))
+	}
+	s.logger.LogAttrs(ctx, level, "demographics", attrs...)
+}
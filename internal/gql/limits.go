@@ -0,0 +1,117 @@
+package gql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	DefaultMaxDepth      = 6
+	DefaultMaxComplexity = 20000
+
+	// emailsPerPerson is the assumed fan-out of an unpaginated emails list.
+	emailsPerPerson = 5
+)
+
+// checkLimits rejects operations nested deeper than s.MaxDepth or whose
+// estimated cost exceeds s.MaxComplexity. Each field costs 1; a list field
+// multiplies the cost of its selection by its page size. Introspection is
+// exempt so tooling keeps working.
+func (s *Schema) checkLimits(doc *ast.Document, op *ast.OperationDefinition, vars map[string]any) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	c := costCounter{fragments: fragments, vars: vars}
+	depth, cost := c.selectionSet(op.SelectionSet, 0)
+	if depth > s.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, s.MaxDepth)
+	}
+	if cost > s.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", cost, s.MaxComplexity)
+	}
+	return nil
+}
+
+type costCounter struct {
+	fragments map[string]*ast.FragmentDefinition
+	vars      map[string]any
+	// visiting guards against fragment cycles, which validation also rejects.
+	visiting []string
+}
+
+// selectionSet returns the depth and cost of set nested below a field at
+// the given depth.
+func (c *costCounter) selectionSet(set *ast.SelectionSet, depth int) (int, int) {
+	if set == nil {
+		return depth, 0
+	}
+	maxDepth, cost := depth, 0
+	for _, sel := range set.Selections {
+		var d, n int
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, n = c.selectionSet(s.SelectionSet, depth+1)
+			n = 1 + c.multiplier(s)*n
+		case *ast.InlineFragment:
+			d, n = c.selectionSet(s.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			f, ok := c.fragments[name]
+			if !ok || slices.Contains(c.visiting, name) {
+				continue
+			}
+			c.visiting = append(c.visiting, name)
+			d, n = c.selectionSet(f.SelectionSet, depth)
+			c.visiting = c.visiting[:len(c.visiting)-1]
+		}
+		maxDepth = max(maxDepth, d)
+		cost += n
+	}
+	return maxDepth, cost
+}
+
+// multiplier is how many times a field's selection runs per parent.
+func (c *costCounter) multiplier(f *ast.Field) int {
+	switch f.Name.Value {
+	case "people", "friends", "mutualFriends":
+		return c.limitArg(f)
+	case "emails":
+		return emailsPerPerson
+	}
+	return 1
+}
+
+func (c *costCounter) limitArg(f *ast.Field) int {
+	limit := httputil.DefaultLimit
+	for _, a := range f.Arguments {
+		if a.Name.Value != "limit" {
+			continue
+		}
+		switch v := a.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				limit = n
+			}
+		case *ast.Variable:
+			switch n := c.vars[v.Name.Value].(type) {
+			case int:
+				limit = n
+			case float64:
+				limit = int(n)
+			}
+		}
+	}
+	return max(1, min(limit, httputil.MaxLimit))
+}
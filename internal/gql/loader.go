@@ -0,0 +1,96 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+)
+
+// loader batches lookups DataLoader-style. Resolvers call load, which only
+// queues the key and returns a thunk; graphql-go runs thunks after every
+// sibling field has been resolved, so the first thunk fetches all queued
+// keys in one round trip.
+type loader[V any] struct {
+	fetch func(ctx context.Context, ids []int64) (map[int64]V, error)
+
+	mu      sync.Mutex
+	pending []int64
+	done    map[int64]V
+	errs    map[int64]error
+}
+
+func newLoader[V any](fetch func(ctx context.Context, ids []int64) (map[int64]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, done: map[int64]V{}, errs: map[int64]error{}}
+}
+
+func (l *loader[V]) load(ctx context.Context, id int64) func() (V, error) {
+	l.mu.Lock()
+	if _, ok := l.done[id]; !ok {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if v, ok := l.done[id]; ok {
+			return v, nil
+		}
+		if err, ok := l.errs[id]; ok {
+			return *new(V), err
+		}
+
+		batch := l.pending
+		l.pending = nil
+		got, err := l.fetch(ctx, batch)
+		for _, k := range batch {
+			if err != nil {
+				l.errs[k] = err
+				continue
+			}
+			// Missing keys are cached as the zero value.
+			l.done[k] = got[k]
+		}
+		if err != nil {
+			return *new(V), err
+		}
+		return l.done[id], nil
+	}
+}
+
+// loaders live for a single request so results are never served stale.
+type loaders struct {
+	people  *loader[*models.Person]
+	emails  *loader[[]models.Email]
+	friends *loader[[]models.Person]
+}
+
+func newLoaders(st *store.Store) *loaders {
+	return &loaders{
+		people: newLoader(func(ctx context.Context, ids []int64) (map[int64]*models.Person, error) {
+			ps, err := st.PeopleByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int64]*models.Person, len(ps))
+			for i := range ps {
+				out[ps[i].ID] = &ps[i]
+			}
+			return out, nil
+		}),
+		emails:  newLoader(st.EmailsByPersonIDs),
+		friends: newLoader(st.FriendsByPersonIDs),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,247 @@
+package gql
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/graphql-go/graphql"
+)
+
+// Mutations mirror the REST endpoints, including their scopes.
+
+var emailInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "EmailInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"email":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"isPrimary": &graphql.InputObjectFieldConfig{Type: graphql.Boolean, DefaultValue: false},
+	},
+})
+
+var createPersonInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreatePersonInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"firstName":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"middleName":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"lastName":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"gender":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"nationality": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"age":         &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"emails":      &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(emailInputType))},
+	},
+})
+
+var updatePersonInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdatePersonInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"firstName":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"middleName":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"lastName":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"gender":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"nationality": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"age":         &graphql.InputObjectFieldConfig{Type: graphql.Int},
+	},
+})
+
+func (s *Schema) mutationType() *graphql.Object {
+	idArgs := func(names ...string) graphql.FieldConfigArgument {
+		out := graphql.FieldConfigArgument{}
+		for _, n := range names {
+			out[n] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+		}
+		return out
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPerson": &graphql.Field{
+				Type:    graphql.NewNonNull(s.person),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createPersonInputType)}},
+				Resolve: s.createPerson,
+			},
+			"updatePerson": &graphql.Field{
+				Type: s.person,
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updatePersonInputType)},
+				},
+				Resolve: s.updatePerson,
+			},
+			"addEmail": &graphql.Field{
+				Type: graphql.NewNonNull(emailType),
+				Args: graphql.FieldConfigArgument{
+					"personId":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"email":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"isPrimary": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: s.addEmail,
+			},
+			"deleteEmail": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArgs("personId", "emailId"),
+				Resolve: s.deleteEmail,
+			},
+			"addFriend": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArgs("id", "friendId"),
+				Resolve: s.addFriend,
+			},
+			"removeFriend": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArgs("id", "friendId"),
+				Resolve: s.removeFriend,
+			},
+		},
+	})
+}
+
+func (s *Schema) createPerson(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopePeopleWrite); err != nil {
+		return nil, err
+	}
+	in, _ := p.Args["input"].(map[string]any)
+	firstName, lastName := stringArg(in, "firstName"), stringArg(in, "lastName")
+	if firstName == "" || lastName == "" {
+		return nil, errors.New("firstName and lastName are required")
+	}
+
+	age, gender, nationality := s.demo.Complete(p.Context, firstName, optInt(in, "age"), optString(in, "gender"), optString(in, "nationality"))
+
+	id, err := s.st.InsertPerson(p.Context, firstName, optString(in, "middleName"), lastName, gender, nationality, age)
+	if err != nil {
+		return nil, err
+	}
+	emails, _ := in["emails"].([]any)
+	for _, e := range emails {
+		em, _ := e.(map[string]any)
+		addr := stringArg(em, "email")
+		if addr == "" {
+			continue
+		}
+		primary, _ := em["isPrimary"].(bool)
+		if _, err := s.st.InsertEmail(p.Context, id, addr, primary); err != nil {
+			return nil, err
+		}
+	}
+	return s.loadPerson(p.Context, id), nil
+}
+
+func (s *Schema) updatePerson(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopePeopleWrite); err != nil {
+		return nil, err
+	}
+	id, err := idArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	in, _ := p.Args["input"].(map[string]any)
+	aff, err := s.st.UpdatePerson(p.Context, id, models.UpdatePersonRequest{
+		FirstName:   optString(in, "firstName"),
+		MiddleName:  optString(in, "middleName"),
+		LastName:    optString(in, "lastName"),
+		Gender:      optString(in, "gender"),
+		Nationality: optString(in, "nationality"),
+		Age:         optInt(in, "age"),
+	})
+	if err != nil || aff == 0 {
+		return nil, err
+	}
+	return s.loadPerson(p.Context, id), nil
+}
+
+func (s *Schema) addEmail(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopeEmailsWrite); err != nil {
+		return nil, err
+	}
+	personID, err := idArg(p.Args, "personId")
+	if err != nil {
+		return nil, err
+	}
+	addr := stringArg(p.Args, "email")
+	if addr == "" {
+		return nil, errors.New("email required")
+	}
+	primary, _ := p.Args["isPrimary"].(bool)
+	emailID, err := s.st.InsertEmail(p.Context, personID, addr, primary)
+	if err != nil {
+		return nil, err
+	}
+	return s.st.GetEmailByID(p.Context, emailID)
+}
+
+func (s *Schema) deleteEmail(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopeEmailsWrite); err != nil {
+		return nil, err
+	}
+	personID, err := idArg(p.Args, "personId")
+	if err != nil {
+		return nil, err
+	}
+	emailID, err := idArg(p.Args, "emailId")
+	if err != nil {
+		return nil, err
+	}
+	aff, err := s.st.DeleteEmail(p.Context, personID, emailID)
+	return aff > 0, err
+}
+
+func (s *Schema) addFriend(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopeFriendsWrite); err != nil {
+		return nil, err
+	}
+	id, friendID, err := friendArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if id == friendID {
+		return nil, errors.New("cannot befriend self")
+	}
+	if err := s.st.AddFriend(p.Context, id, friendID); err != nil {
+		if errors.Is(err, store.ErrBlocked) {
+			return nil, errors.New("friendship blocked")
+		}
+		return nil, err
+	}
+	return true, nil
+}
+
+func (s *Schema) removeFriend(p graphql.ResolveParams) (any, error) {
+	if err := requireScope(p.Context, auth.ScopeFriendsWrite); err != nil {
+		return nil, err
+	}
+	id, friendID, err := friendArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	aff, err := s.st.RemoveFriend(p.Context, id, friendID)
+	return aff > 0, err
+}
+
+func friendArgs(args map[string]any) (int64, int64, error) {
+	id, err := idArg(args, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+	friendID, err := idArg(args, "friendId")
+	return id, friendID, err
+}
+
+func optString(args map[string]any, name string) *string {
+	v, ok := args[name].(string)
+	if !ok {
+		return nil
+	}
+	v = strings.TrimSpace(v)
+	return &v
+}
+
+func optInt(args map[string]any, name string) *int {
+	v, ok := args[name].(int)
+	if !ok {
+		return nil
+	}
+	return &v
+}
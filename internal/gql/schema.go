@@ -0,0 +1,331 @@
+// Package gql serves the people graph over GraphQL on top of the same store
+// as the REST API. Nested emails and friends are batched per request.
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/external/demographics"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Schema struct {
+	MaxDepth      int
+	MaxComplexity int
+
+	st     *store.Store
+	demo   *demographics.Service
+	person *graphql.Object
+	schema graphql.Schema
+}
+
+// New builds the schema. It panics if the schema is invalid, which can only
+// be a programming error.
+func New(st *store.Store, demo *demographics.Service) *Schema {
+	s := &Schema{
+		MaxDepth:      DefaultMaxDepth,
+		MaxComplexity: DefaultMaxComplexity,
+		st:            st,
+		demo:          demo,
+	}
+	s.person = s.personType()
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:    s.queryType(),
+		Mutation: s.mutationType(),
+	})
+	if err != nil {
+		panic("gql: " + err.Error())
+	}
+	s.schema = schema
+	return s
+}
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	// QueryOnly rejects mutations, for requests made with GET.
+	QueryOnly bool `json:"-"`
+}
+
+// Execute runs one GraphQL request. Documents over the depth or complexity
+// limits are rejected before any resolver runs.
+func (s *Schema) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if v := graphql.ValidateDocument(&s.schema, doc, nil); !v.IsValid {
+		return &graphql.Result{Errors: v.Errors}
+	}
+	if op := operation(doc, req.OperationName); op != nil {
+		if req.QueryOnly && op.Operation == ast.OperationTypeMutation {
+			return &graphql.Result{Errors: gqlerrors.FormatErrors(errors.New("mutations must be sent with POST"))}
+		}
+		if err := s.checkLimits(doc, op, req.Variables); err != nil {
+			return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+		}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, newLoaders(s.st)),
+	})
+}
+
+// operation picks the operation to run, or nil to let execution report an
+// unknown or ambiguous one.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && (name == "" || (op.Name != nil && op.Name.Value == name)) {
+			return op
+		}
+	}
+	return nil
+}
+
+// ---------- types
+
+var emailType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Email",
+	Fields: graphql.Fields{
+		"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: emailField(func(e models.Email) any { return e.ID })},
+		"personId":  &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: emailField(func(e models.Email) any { return e.PersonID })},
+		"email":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: emailField(func(e models.Email) any { return e.Email })},
+		"isPrimary": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: emailField(func(e models.Email) any { return e.IsPrimary })},
+		"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: emailField(func(e models.Email) any { return e.CreatedAt })},
+	},
+})
+
+func emailField(get func(models.Email) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(models.Email)), nil
+	}
+}
+
+func personField(get func(*models.Person) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*models.Person)), nil
+	}
+}
+
+var pageArgs = graphql.FieldConfigArgument{
+	"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: httputil.DefaultLimit},
+	"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+}
+
+func (s *Schema) personType() *graphql.Object {
+	person := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Person",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: personField(func(p *models.Person) any { return p.ID })},
+			"firstName":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: personField(func(p *models.Person) any { return p.FirstName })},
+			"middleName":  &graphql.Field{Type: graphql.String, Resolve: personField(func(p *models.Person) any { return p.MiddleName })},
+			"lastName":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: personField(func(p *models.Person) any { return p.LastName })},
+			"gender":      &graphql.Field{Type: graphql.String, Resolve: personField(func(p *models.Person) any { return p.Gender })},
+			"nationality": &graphql.Field{Type: graphql.String, Resolve: personField(func(p *models.Person) any { return p.Nationality })},
+			"age":         &graphql.Field{Type: graphql.Int, Resolve: personField(func(p *models.Person) any { return p.Age })},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: personField(func(p *models.Person) any { return p.CreatedAt })},
+			"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: personField(func(p *models.Person) any { return p.UpdatedAt })},
+			"emails": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(emailType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					thunk := loadersFrom(p.Context).emails.load(p.Context, p.Source.(*models.Person).ID)
+					return func() (any, error) {
+						es, err := thunk()
+						return nonNil(es), err
+					}, nil
+				},
+			},
+		},
+	})
+
+	// Fields that refer back to Person are added once the type exists.
+	person.AddFieldConfig("friends", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(person))),
+		Args: pageArgs,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			limit, offset, err := pageFromArgs(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			thunk := loadersFrom(p.Context).friends.load(p.Context, p.Source.(*models.Person).ID)
+			return func() (any, error) {
+				fs, err := thunk()
+				if err != nil {
+					return nil, err
+				}
+				return peoplePtrs(window(fs, limit, offset)), nil
+			}, nil
+		},
+	})
+	person.AddFieldConfig("friendsCount", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Int),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			thunk := loadersFrom(p.Context).friends.load(p.Context, p.Source.(*models.Person).ID)
+			return func() (any, error) {
+				fs, err := thunk()
+				return len(fs), err
+			}, nil
+		},
+	})
+	mutualArgs := graphql.FieldConfigArgument{"with": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}}
+	for k, v := range pageArgs {
+		mutualArgs[k] = v
+	}
+	person.AddFieldConfig("mutualFriends", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(person))),
+		Args: mutualArgs,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			other, err := idArg(p.Args, "with")
+			if err != nil {
+				return nil, err
+			}
+			limit, offset, err := pageFromArgs(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			out, err := s.st.MutualFriends(p.Context, p.Source.(*models.Person).ID, other, limit, offset)
+			return peoplePtrs(out), err
+		},
+	})
+	return person
+}
+
+func (s *Schema) queryType() *graphql.Object {
+	person := s.person
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"person": &graphql.Field{
+				Type: person,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if err := requireScope(p.Context, auth.ScopePeopleRead); err != nil {
+						return nil, err
+					}
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return s.loadPerson(p.Context, id), nil
+				},
+			},
+			"people": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(person))),
+				Args: graphql.FieldConfigArgument{
+					"lastName":    &graphql.ArgumentConfig{Type: graphql.String},
+					"nationality": &graphql.ArgumentConfig{Type: graphql.String},
+					"gender":      &graphql.ArgumentConfig{Type: graphql.String},
+					"limit":       pageArgs["limit"],
+					"offset":      pageArgs["offset"],
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if err := requireScope(p.Context, auth.ScopePeopleRead); err != nil {
+						return nil, err
+					}
+					limit, offset, err := pageFromArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					f := store.GraphFilter{
+						LastName:    stringArg(p.Args, "lastName"),
+						Nationality: stringArg(p.Args, "nationality"),
+						Gender:      stringArg(p.Args, "gender"),
+					}
+					out, err := s.st.SearchPeople(p.Context, f, limit, offset)
+					return peoplePtrs(out), err
+				},
+			},
+		},
+	})
+}
+
+// loadPerson returns a thunk resolving to the person, or nil if missing.
+func (s *Schema) loadPerson(ctx context.Context, id int64) func() (any, error) {
+	thunk := loadersFrom(ctx).people.load(ctx, id)
+	return func() (any, error) {
+		p, err := thunk()
+		if p == nil {
+			return nil, err
+		}
+		return p, err
+	}
+}
+
+// ---------- helpers
+
+var errForbidden = errors.New("forbidden")
+
+func requireScope(ctx context.Context, scope string) error {
+	if p, ok := auth.FromContext(ctx); ok && p.Has(scope) {
+		return nil
+	}
+	return errForbidden
+}
+
+func idArg(args map[string]any, name string) (int64, error) {
+	s, _ := args[name].(string)
+	id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid " + name)
+	}
+	return id, nil
+}
+
+func stringArg(args map[string]any, name string) string {
+	s, _ := args[name].(string)
+	return strings.TrimSpace(s)
+}
+
+// pageFromArgs applies the same bounds as httputil.ParsePage.
+func pageFromArgs(args map[string]any) (limit, offset int, err error) {
+	limit, _ = args["limit"].(int)
+	offset, _ = args["offset"].(int)
+	if limit <= 0 {
+		return 0, 0, errors.New("limit must be a positive integer")
+	}
+	if limit > httputil.MaxLimit {
+		return 0, 0, fmt.Errorf("limit must be at most %d", httputil.MaxLimit)
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("offset must be a non-negative integer")
+	}
+	return limit, offset, nil
+}
+
+func window[T any](s []T, limit, offset int) []T {
+	if offset >= len(s) {
+		return nil
+	}
+	return s[offset:min(len(s), offset+limit)]
+}
+
+func peoplePtrs(ps []models.Person) []*models.Person {
+	out := make([]*models.Person, len(ps))
+	for i := range ps {
+		out[i] = &ps[i]
+	}
+	return out
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
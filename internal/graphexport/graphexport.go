@@ -0,0 +1,307 @@
+// Package graphexport serialises the friendship graph for offline analysis
+// tools (Gephi, networkx, Graphviz). Encoders write nodes first, then edges,
+// so callers can stream both straight from the database.
+package graphexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatGraphML = "graphml"
+	FormatGEXF    = "gexf"
+	FormatDOT     = "dot"
+)
+
+type Encoder interface {
+	Node(p models.Person) error
+	Edge(f models.Friendship) error
+	// Close writes the trailer and flushes; it must be called exactly once.
+	Close() error
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatGraphML:
+		return "application/graphml+xml"
+	case FormatGEXF:
+		return "application/gexf+xml"
+	case FormatDOT:
+		return "text/vnd.graphviz"
+	default:
+		return "application/json"
+	}
+}
+
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case FormatJSON, "":
+		return &jsonEncoder{w: bw}, nil
+	case FormatGraphML:
+		return &graphmlEncoder{w: bw}, nil
+	case FormatGEXF:
+		return &gexfEncoder{w: bw}, nil
+	case FormatDOT:
+		return &dotEncoder{w: bw}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type attr struct {
+	name, typ, value string
+}
+
+func personAttrs(p models.Person) []attr {
+	out := []attr{
+		{"first_name", "string", p.FirstName},
+		{"last_name", "string", p.LastName},
+	}
+	if p.MiddleName != nil {
+		out = append(out, attr{"middle_name", "string", *p.MiddleName})
+	}
+	if p.Gender != nil {
+		out = append(out, attr{"gender", "string", *p.Gender})
+	}
+	if p.Nationality != nil {
+		out = append(out, attr{"nationality", "string", *p.Nationality})
+	}
+	if p.Age != nil {
+		out = append(out, attr{"age", "int", strconv.Itoa(*p.Age)})
+	}
+	out = append(out,
+		attr{"created_at", "string", p.CreatedAt.UTC().Format(time.RFC3339)},
+		attr{"updated_at", "string", p.UpdatedAt.UTC().Format(time.RFC3339)},
+	)
+	return out
+}
+
+// nodeKeys lists every attribute personAttrs may emit, for formats that
+// declare their schema up front.
+var nodeKeys = []attr{
+	{name: "first_name", typ: "string"},
+	{name: "middle_name", typ: "string"},
+	{name: "last_name", typ: "string"},
+	{name: "gender", typ: "string"},
+	{name: "nationality", typ: "string"},
+	{name: "age", typ: "int"},
+	{name: "created_at", typ: "string"},
+	{name: "updated_at", typ: "string"},
+}
+
+func label(p models.Person) string {
+	return p.FirstName + " " + p.LastName
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// ---------- json
+
+type jsonEncoder struct {
+	w       *bufio.Writer
+	started bool
+	edges   bool
+	n       int
+}
+
+func (e *jsonEncoder) section(name string) {
+	if !e.started {
+		e.w.WriteString(`{"nodes":[`)
+		e.started = true
+	}
+	if name == "edges" && !e.edges {
+		e.w.WriteString(`],"edges":[`)
+		e.edges = true
+		e.n = 0
+	}
+	if e.n > 0 {
+		e.w.WriteByte(',')
+	}
+	e.n++
+}
+
+func (e *jsonEncoder) Node(p models.Person) error {
+	e.section("nodes")
+	return e.write(p)
+}
+
+func (e *jsonEncoder) Edge(f models.Friendship) error {
+	e.section("edges")
+	return e.write(f)
+}
+
+func (e *jsonEncoder) write(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if !e.started {
+		e.w.WriteString(`{"nodes":[`)
+	}
+	if !e.edges {
+		e.w.WriteString(`],"edges":[`)
+	}
+	e.w.WriteString("]}\n")
+	return e.w.Flush()
+}
+
+// ---------- graphml
+
+type graphmlEncoder struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func (e *graphmlEncoder) header() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.w.WriteString(xml.Header)
+	e.w.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, k := range nodeKeys {
+		fmt.Fprintf(e.w, `  <key id="%s" for="node" attr.name="%s" attr.type="%s"/>`+"\n", k.name, k.name, k.typ)
+	}
+	e.w.WriteString(`  <key id="edge_created_at" for="edge" attr.name="created_at" attr.type="string"/>` + "\n")
+	e.w.WriteString(`  <graph id="people" edgedefault="undirected">` + "\n")
+}
+
+func (e *graphmlEncoder) Node(p models.Person) error {
+	e.header()
+	fmt.Fprintf(e.w, `    <node id="n%d">`+"\n", p.ID)
+	for _, a := range personAttrs(p) {
+		fmt.Fprintf(e.w, `      <data key="%s">%s</data>`+"\n", a.name, xmlEscape(a.value))
+	}
+	_, err := e.w.WriteString("    </node>\n")
+	return err
+}
+
+func (e *graphmlEncoder) Edge(f models.Friendship) error {
+	e.header()
+	_, err := fmt.Fprintf(e.w, `    <edge source="n%d" target="n%d"><data key="edge_created_at">%s</data></edge>`+"\n",
+		f.UserID, f.FriendID, f.CreatedAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+func (e *graphmlEncoder) Close() error {
+	e.header()
+	e.w.WriteString("  </graph>\n</graphml>\n")
+	return e.w.Flush()
+}
+
+// ---------- gexf
+
+type gexfEncoder struct {
+	w     *bufio.Writer
+	state int // 0 = nothing written, 1 = inside <nodes>, 2 = inside <edges>
+}
+
+func (e *gexfEncoder) to(state int) {
+	for e.state < state {
+		switch e.state {
+		case 0:
+			e.w.WriteString(xml.Header)
+			e.w.WriteString(`<gexf xmlns="http://gexf.net/1.3" version="1.3">` + "\n")
+			e.w.WriteString(`  <graph mode="static" defaultedgetype="undirected">` + "\n")
+			e.w.WriteString(`    <attributes class="node">` + "\n")
+			for _, k := range nodeKeys {
+				typ := k.typ
+				if typ == "int" {
+					typ = "integer"
+				}
+				fmt.Fprintf(e.w, `      <attribute id="%s" title="%s" type="%s"/>`+"\n", k.name, k.name, typ)
+			}
+			e.w.WriteString("    </attributes>\n")
+			e.w.WriteString(`    <attributes class="edge">` + "\n")
+			e.w.WriteString(`      <attribute id="created_at" title="created_at" type="string"/>` + "\n")
+			e.w.WriteString("    </attributes>\n")
+			e.w.WriteString("    <nodes>\n")
+		case 1:
+			e.w.WriteString("    </nodes>\n    <edges>\n")
+		}
+		e.state++
+	}
+}
+
+func (e *gexfEncoder) Node(p models.Person) error {
+	e.to(1)
+	fmt.Fprintf(e.w, `      <node id="%d" label="%s"><attvalues>`, p.ID, xmlEscape(label(p)))
+	for _, a := range personAttrs(p) {
+		fmt.Fprintf(e.w, `<attvalue for="%s" value="%s"/>`, a.name, xmlEscape(a.value))
+	}
+	_, err := e.w.WriteString("</attvalues></node>\n")
+	return err
+}
+
+func (e *gexfEncoder) Edge(f models.Friendship) error {
+	e.to(2)
+	_, err := fmt.Fprintf(e.w, `      <edge id="%d-%d" source="%d" target="%d"><attvalues><attvalue for="created_at" value="%s"/></attvalues></edge>`+"\n",
+		f.UserID, f.FriendID, f.UserID, f.FriendID, f.CreatedAt.UTC().Format(time.RFC3339))
+	return err
+}
+
+func (e *gexfEncoder) Close() error {
+	e.to(2)
+	e.w.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	return e.w.Flush()
+}
+
+// ---------- dot
+
+type dotEncoder struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func (e *dotEncoder) header() {
+	if !e.started {
+		e.started = true
+		e.w.WriteString("graph people {\n")
+	}
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func (e *dotEncoder) Node(p models.Person) error {
+	e.header()
+	parts := []string{"label=" + dotQuote(label(p))}
+	for _, a := range personAttrs(p) {
+		parts = append(parts, a.name+"="+dotQuote(a.value))
+	}
+	_, err := fmt.Fprintf(e.w, "  %d [%s];\n", p.ID, strings.Join(parts, ", "))
+	return err
+}
+
+func (e *dotEncoder) Edge(f models.Friendship) error {
+	e.header()
+	_, err := fmt.Fprintf(e.w, "  %d -- %d [created_at=%s];\n",
+		f.UserID, f.FriendID, dotQuote(f.CreatedAt.UTC().Format(time.RFC3339)))
+	return err
+}
+
+func (e *dotEncoder) Close() error {
+	e.header()
+	e.w.WriteString("}\n")
+	return e.w.Flush()
+}
@@ -0,0 +1,107 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	peoplev1 "github.com/Kirill-Pinyaev/people-api/api/people/v1"
+	"github.com/Kirill-Pinyaev/people-api/internal/app"
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes mirrors the auth.Require groups on the REST routes. A method
+// missing here is refused, so a new RPC stays closed until it is given a
+// scope.
+var methodScopes = map[string]string{
+	peoplev1.PeopleService_GetPerson_FullMethodName:    auth.ScopePeopleRead,
+	peoplev1.PeopleService_ListPeople_FullMethodName:   auth.ScopePeopleRead,
+	peoplev1.PeopleService_ListEmails_FullMethodName:   auth.ScopePeopleRead,
+	peoplev1.PeopleService_ListFriends_FullMethodName:  auth.ScopePeopleRead,
+	peoplev1.PeopleService_ExportGraph_FullMethodName:  auth.ScopePeopleRead,
+	peoplev1.PeopleService_CreatePerson_FullMethodName: auth.ScopePeopleWrite,
+	peoplev1.PeopleService_UpdatePerson_FullMethodName: auth.ScopePeopleWrite,
+	peoplev1.PeopleService_AddEmail_FullMethodName:     auth.ScopeEmailsWrite,
+	peoplev1.PeopleService_DeleteEmail_FullMethodName:  auth.ScopeEmailsWrite,
+	peoplev1.PeopleService_AddFriend_FullMethodName:    auth.ScopeFriendsWrite,
+	peoplev1.PeopleService_RemoveFriend_FullMethodName: auth.ScopeFriendsWrite,
+}
+
+// publicServices are served without credentials, matched by method prefix.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+func unaryAuth(a *app.App) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, a.Auth, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(a *app.App) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), a.Auth, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize authenticates the "authorization" metadata and checks the
+// method's scope.
+func authorize(ctx context.Context, a auth.Authenticator, method string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "method %s has no scope", method)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	token, ok := bearerToken(md)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	p, err := a.Authenticate(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	logging.Set(ctx, slog.String("subject", p.Subject))
+	if !p.Has(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "scope %s required", scope)
+	}
+	return auth.WithPrincipal(ctx, p), nil
+}
+
+func bearerToken(md metadata.MD) (string, bool) {
+	vals := md.Get("authorization")
+	if len(vals) == 0 {
+		return "", false
+	}
+	scheme, token, ok := strings.Cut(vals[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	peoplev1 "github.com/Kirill-Pinyaev/people-api/api/people/v1"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toPerson(p models.Person) *peoplev1.Person {
+	out := &peoplev1.Person{
+		Id:           p.ID,
+		FirstName:    p.FirstName,
+		MiddleName:   p.MiddleName,
+		LastName:     p.LastName,
+		Gender:       p.Gender,
+		Nationality:  p.Nationality,
+		CreatedAt:    timestamppb.New(p.CreatedAt),
+		UpdatedAt:    timestamppb.New(p.UpdatedAt),
+		FriendsCount: int32(p.FriendsCount),
+	}
+	if p.Age != nil {
+		age := int32(*p.Age)
+		out.Age = &age
+	}
+	for _, e := range p.Emails {
+		out.Emails = append(out.Emails, toEmail(e))
+	}
+	return out
+}
+
+func toEmail(e models.Email) *peoplev1.Email {
+	return &peoplev1.Email{
+		Id:        e.ID,
+		PersonId:  e.PersonID,
+		Email:     e.Email,
+		IsPrimary: e.IsPrimary,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+}
+
+func toFriendship(f models.Friendship) *peoplev1.Friendship {
+	return &peoplev1.Friendship{
+		UserId:    f.UserID,
+		FriendId:  f.FriendID,
+		CreatedAt: timestamppb.New(f.CreatedAt),
+	}
+}
+
+func toFilter(f *peoplev1.PeopleFilter) store.GraphFilter {
+	return store.GraphFilter{
+		LastName:    f.GetLastName(),
+		Nationality: f.GetNationality(),
+		Gender:      f.GetGender(),
+	}
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
@@ -0,0 +1,61 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/logging"
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func unaryLog(l *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = requestContext(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, l, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func streamLog(l *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := requestContext(ss.Context())
+		start := time.Now()
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, l, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// requestContext carries the x-request-id metadata, if any, the same way
+// middleware.RequestID does over HTTP, so logs and audit rows correlate.
+func requestContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get("x-request-id"); len(ids) > 0 && ids[0] != "" {
+		ctx = context.WithValue(ctx, middleware.RequestIDKey, ids[0])
+	}
+	return logging.WithFields(ctx)
+}
+
+func logCall(ctx context.Context, l *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	l.LogAttrs(ctx, level, "rpc",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	)
+}
@@ -0,0 +1,261 @@
+// Package grpcapi serves people.v1 over gRPC from the same App as the REST
+// API. Status codes follow the REST handlers: 400 is InvalidArgument, 401
+// Unauthenticated, 403 PermissionDenied, 404 NotFound and 500 Internal.
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	peoplev1 "github.com/Kirill-Pinyaev/people-api/api/people/v1"
+	"github.com/Kirill-Pinyaev/people-api/internal/app"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server is the gRPC server with the people service, health checking and
+// reflection registered.
+type Server struct {
+	*grpc.Server
+	health *health.Server
+}
+
+func New(a *app.App) *Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryLog(a.Logger), unaryAuth(a)),
+		grpc.ChainStreamInterceptor(streamLog(a.Logger), streamAuth(a)),
+	)
+	peoplev1.RegisterPeopleServiceServer(srv, &server{a: a})
+
+	hs := health.NewServer()
+	hs.SetServingStatus(peoplev1.PeopleService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+
+	reflection.Register(srv)
+	return &Server{Server: srv, health: hs}
+}
+
+// Shutdown reports NOT_SERVING, then waits for in-flight RPCs until ctx is
+// done, after which the remaining ones are cancelled.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+		<-done
+	}
+}
+
+type server struct {
+	peoplev1.UnimplementedPeopleServiceServer
+	a *app.App
+}
+
+// --------- People
+
+func (s *server) GetPerson(ctx context.Context, req *peoplev1.GetPersonRequest) (*peoplev1.Person, error) {
+	p, err := s.a.Store.GetPersonWithDetails(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.notFoundOrMerged(ctx, req.GetId())
+		}
+		return nil, status.Errorf(codes.Internal, "get: %v", err)
+	}
+	return toPerson(p), nil
+}
+
+// notFoundOrMerged names the survivor when id was merged away; REST answers
+// the same case with a redirect.
+func (s *server) notFoundOrMerged(ctx context.Context, id int64) error {
+	to, err := s.a.Store.ResolveRedirect(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "not found")
+		}
+		return status.Errorf(codes.Internal, "resolve redirect: %v", err)
+	}
+	return status.Errorf(codes.NotFound, "person %d was merged into %d", id, to)
+}
+
+func (s *server) ListPeople(req *peoplev1.ListPeopleRequest, stream grpc.ServerStreamingServer[peoplev1.Person]) error {
+	err := s.a.Store.EachPerson(stream.Context(), toFilter(req.GetFilter()), func(p models.Person) error {
+		return stream.Send(toPerson(p))
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "list: %v", err)
+	}
+	return nil
+}
+
+func (s *server) CreatePerson(ctx context.Context, req *peoplev1.CreatePersonRequest) (*peoplev1.Person, error) {
+	firstName := strings.TrimSpace(req.GetFirstName())
+	lastName := strings.TrimSpace(req.GetLastName())
+	if firstName == "" || lastName == "" {
+		return nil, status.Error(codes.InvalidArgument, "first_name and last_name are required")
+	}
+
+	age, gender, nationality := s.a.Demographics.Complete(ctx, firstName, intPtr(req.Age), req.Gender, req.Nationality)
+	id, err := s.a.Store.InsertPerson(ctx, firstName, req.MiddleName, lastName, gender, nationality, age)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "insert person: %v", err)
+	}
+	for _, em := range req.GetEmails() {
+		if strings.TrimSpace(em.GetEmail()) == "" {
+			continue
+		}
+		if _, err := s.a.Store.InsertEmail(ctx, id, em.GetEmail(), em.GetIsPrimary()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "insert email %s: %v", em.GetEmail(), err)
+		}
+	}
+
+	p, err := s.a.Store.GetPersonWithDetails(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get person: %v", err)
+	}
+	return toPerson(p), nil
+}
+
+func (s *server) UpdatePerson(ctx context.Context, req *peoplev1.UpdatePersonRequest) (*peoplev1.Person, error) {
+	aff, err := s.a.Store.UpdatePerson(ctx, req.GetId(), models.UpdatePersonRequest{
+		FirstName:   req.FirstName,
+		MiddleName:  req.MiddleName,
+		LastName:    req.LastName,
+		Gender:      req.Gender,
+		Nationality: req.Nationality,
+		Age:         intPtr(req.Age),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update: %v", err)
+	}
+	if aff == 0 {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	p, err := s.a.Store.GetPersonWithDetails(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get: %v", err)
+	}
+	return toPerson(p), nil
+}
+
+// --------- Emails
+
+func (s *server) ListEmails(ctx context.Context, req *peoplev1.ListEmailsRequest) (*peoplev1.ListEmailsResponse, error) {
+	es, err := s.a.Store.ListEmails(ctx, req.GetPersonId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list: %v", err)
+	}
+	out := &peoplev1.ListEmailsResponse{}
+	for _, e := range es {
+		out.Emails = append(out.Emails, toEmail(e))
+	}
+	return out, nil
+}
+
+func (s *server) AddEmail(ctx context.Context, req *peoplev1.AddEmailRequest) (*peoplev1.Email, error) {
+	if strings.TrimSpace(req.GetEmail()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "email required")
+	}
+	id, err := s.a.Store.InsertEmail(ctx, req.GetPersonId(), req.GetEmail(), req.GetIsPrimary())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "insert: %v", err)
+	}
+	e, err := s.a.Store.GetEmailByID(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "scan: %v", err)
+	}
+	return toEmail(e), nil
+}
+
+func (s *server) DeleteEmail(ctx context.Context, req *peoplev1.DeleteEmailRequest) (*emptypb.Empty, error) {
+	aff, err := s.a.Store.DeleteEmail(ctx, req.GetPersonId(), req.GetEmailId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete: %v", err)
+	}
+	if aff == 0 {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// --------- Friends
+
+func (s *server) ListFriends(req *peoplev1.ListFriendsRequest, stream grpc.ServerStreamingServer[peoplev1.Person]) error {
+	friends, err := s.a.Store.ListFriends(stream.Context(), req.GetPersonId())
+	if err != nil {
+		return status.Errorf(codes.Internal, "list friends: %v", err)
+	}
+	for _, p := range friends {
+		if err := stream.Send(toPerson(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) AddFriend(ctx context.Context, req *peoplev1.AddFriendRequest) (*emptypb.Empty, error) {
+	if req.GetPersonId() == req.GetFriendId() {
+		return nil, status.Error(codes.InvalidArgument, "cannot befriend self")
+	}
+	if err := s.a.Store.AddFriend(ctx, req.GetPersonId(), req.GetFriendId()); err != nil {
+		if errors.Is(err, store.ErrBlocked) {
+			return nil, status.Error(codes.PermissionDenied, "friendship blocked")
+		}
+		return nil, status.Errorf(codes.Internal, "insert: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) RemoveFriend(ctx context.Context, req *peoplev1.RemoveFriendRequest) (*emptypb.Empty, error) {
+	aff, err := s.a.Store.RemoveFriend(ctx, req.GetPersonId(), req.GetFriendId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete: %v", err)
+	}
+	if aff == 0 {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// --------- Export
+
+func (s *server) ExportGraph(req *peoplev1.ExportGraphRequest, stream grpc.ServerStreamingServer[peoplev1.GraphElement]) error {
+	f := toFilter(req.GetFilter())
+	snap, err := s.a.Store.GraphSnapshot(stream.Context())
+	if errors.Is(err, store.ErrTooManyExports) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "export: %v", err)
+	}
+	defer snap.Close()
+
+	err = snap.EachPerson(stream.Context(), f, func(p models.Person) error {
+		return stream.Send(&peoplev1.GraphElement{Element: &peoplev1.GraphElement_Person{Person: toPerson(p)}})
+	})
+	if err == nil {
+		err = snap.EachFriendship(stream.Context(), f, func(fr models.Friendship) error {
+			return stream.Send(&peoplev1.GraphElement{Element: &peoplev1.GraphElement_Friendship{Friendship: toFriendship(fr)}})
+		})
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "export: %v", err)
+	}
+	return nil
+}
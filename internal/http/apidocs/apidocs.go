@@ -0,0 +1,103 @@
+// Package apidocs serves the embedded OpenAPI spec and a Swagger UI for it.
+package apidocs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/swaggest/swgui"
+	"github.com/swaggest/swgui/v5emb"
+	"gopkg.in/yaml.v3"
+)
+
+// UIPath is where the docs UI is mounted.
+const UIPath = "/docs/"
+
+type Docs struct {
+	root *yaml.Node
+	// servers is the index of the value of the top-level "servers" key in
+	// root.Content, or -1.
+	servers int
+}
+
+func New(spec []byte) (*Docs, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("spec is not a YAML mapping")
+	}
+	d := &Docs{root: doc.Content[0], servers: -1}
+	for i := 0; i+1 < len(d.root.Content); i += 2 {
+		if d.root.Content[i].Value == "servers" {
+			d.servers = i + 1
+		}
+	}
+	return d, nil
+}
+
+// YAML serves the spec with its server pointed at the host the request
+// came in on.
+func (d *Docs) YAML(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	_ = enc.Encode(d.forRequest(r))
+	_ = enc.Close()
+}
+
+// JSON serves the same document as JSON.
+func (d *Docs) JSON(w http.ResponseWriter, r *http.Request) {
+	var v any
+	if err := d.forRequest(r).Decode(&v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// UI serves Swagger UI, assets included, under UIPath.
+func (d *Docs) UI() http.Handler {
+	return v5emb.NewWithConfig(swgui.Config{ShowTopBar: false})("People API", "/openapi.json", UIPath)
+}
+
+// forRequest returns the document with servers replaced. Only the top-level
+// mapping is copied; the rest is shared and must not be modified.
+func (d *Docs) forRequest(r *http.Request) *yaml.Node {
+	servers := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "url"},
+			{Kind: yaml.ScalarNode, Value: serverURL(r)},
+		},
+	}}}
+	root := *d.root
+	root.Content = append([]*yaml.Node(nil), d.root.Content...)
+	if d.servers >= 0 {
+		root.Content[d.servers] = servers
+	} else {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "servers"}, servers)
+	}
+	return &root
+}
+
+// serverURL is the API's origin as the client sees it, honouring the
+// X-Forwarded-* headers of a proxy in front.
+func serverURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := r.Header.Get("X-Forwarded-Proto"); p == "http" || p == "https" {
+		scheme = p
+	}
+	host := r.Host
+	if h, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Host"), ","); h != "" {
+		host = strings.TrimSpace(h)
+	}
+	return scheme + "://" + host
+}
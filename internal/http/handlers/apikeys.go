@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// --------- API keys (admin)
+
+func (h *Handlers) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	out, err := h.a.Store.ListAPIKeys(r.Context())
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+func (h *Handlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid json: %v", err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		httputil.Error(w, http.StatusBadRequest, "name required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		httputil.Error(w, http.StatusBadRequest, "at least one scope required")
+		return
+	}
+	for _, s := range req.Scopes {
+		if !auth.ValidScope(s) {
+			httputil.Error(w, http.StatusBadRequest, "unknown scope %q (known: %s)", s, strings.Join(auth.AllScopes, ", "))
+			return
+		}
+	}
+
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "generate key: %v", err)
+		return
+	}
+	k, err := h.a.Store.CreateAPIKey(r.Context(), req.Name, prefix, auth.HashKey(key), req.Scopes)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "insert: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusCreated, models.IssuedAPIKey{APIKey: k, Key: key})
+}
+
+func (h *Handlers) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "key_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid key_id: %v", err)
+		return
+	}
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "generate key: %v", err)
+		return
+	}
+	k, err := h.a.Store.RotateAPIKey(r.Context(), id, prefix, auth.HashKey(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		httputil.Error(w, http.StatusInternalServerError, "rotate: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, models.IssuedAPIKey{APIKey: k, Key: key})
+}
+
+func (h *Handlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "key_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid key_id: %v", err)
+		return
+	}
+	aff, err := h.a.Store.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "revoke: %v", err)
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
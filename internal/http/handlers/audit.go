@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+)
+
+// --------- Audit
+
+func (h *Handlers) ListAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := store.AuditFilter{
+		Entity:   strings.TrimSpace(q.Get("entity")),
+		EntityID: strings.TrimSpace(q.Get("id")),
+		Actor:    strings.TrimSpace(q.Get("actor")),
+	}
+	if f.EntityID != "" && f.Entity == "" {
+		httputil.Error(w, http.StatusBadRequest, "id requires entity")
+		return
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	out, err := h.a.Store.ListAuditEvents(r.Context(), f, page.Limit, page.Offset)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list audit: %v", err)
+		return
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/go-chi/chi/v5"
+)
+
+// --------- Blocks
+
+func (h *Handlers) BlockPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	if id == otherID {
+		httputil.Error(w, http.StatusBadRequest, "cannot block self")
+		return
+	}
+	if !h.peopleExist(w, r, id, otherID) {
+		return
+	}
+	if err := h.a.Store.Block(r.Context(), id, otherID); err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "block: %v", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handlers) UnblockPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	aff, err := h.a.Store.Unblock(r.Context(), id, otherID)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "unblock: %v", err)
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	out, err := h.a.Store.ListBlocked(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list blocks: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
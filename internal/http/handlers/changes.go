@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/changes"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/webhooks"
+	"github.com/gorilla/websocket"
+)
+
+// --------- Change feed
+
+const (
+	changesHeartbeat = 15 * time.Second
+	// changesWriteWait bounds a single write so a stalled client cannot pin
+	// the stream; it is then dropped like any other slow consumer.
+	changesWriteWait = 10 * time.Second
+	sseRetry         = 2 * time.Second
+)
+
+// ChangesStream streams change events as Server-Sent Events. Clients resume
+// by sending the last id they saw as Last-Event-ID.
+func (h *Handlers) ChangesStream(w http.ResponseWriter, r *http.Request) {
+	after, types, err := parseChangesQuery(r, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		slog.WarnContext(r.Context(), "changes stream: flush", "err", err)
+		return
+	}
+
+	err = h.a.Changes.Follow(r.Context(), after, types, changesHeartbeat, func(e *models.OutboxEvent) error {
+		rc.SetWriteDeadline(time.Now().Add(changesWriteWait))
+		if e == nil {
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			return rc.Flush()
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	logFollowErr(r.Context(), "changes stream", err)
+}
+
+// checkOrigin admits browser upgrades from the page's own host and from the
+// origins CORS allows, matched the same way: case-insensitively, with "*"
+// for any origin and at most one "*" inside a pattern.
+func (h *Handlers) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range h.a.Config.HTTP.CORSOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+			len(origin) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangesWebSocket sends the same events as JSON text messages, resuming
+// after ?last_event_id=. Heartbeats are WebSocket pings.
+func (h *Handlers) ChangesWebSocket(w http.ResponseWriter, r *http.Request) {
+	after, types, err := parseChangesQuery(r, "")
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied.
+		return
+	}
+	defer conn.Close()
+
+	// The feed is one-way; reading only serves control frames and notices
+	// the client going away.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(2 * changesHeartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * changesHeartbeat))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = h.a.Changes.Follow(ctx, after, types, changesHeartbeat, func(e *models.OutboxEvent) error {
+		deadline := time.Now().Add(changesWriteWait)
+		if e == nil {
+			return conn.WriteControl(websocket.PingMessage, nil, deadline)
+		}
+		conn.SetWriteDeadline(deadline)
+		return conn.WriteJSON(e)
+	})
+	switch {
+	case errors.Is(err, changes.ErrLagged):
+		msg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind; resume with last_event_id")
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(changesWriteWait))
+	case errors.Is(err, changes.ErrClosed):
+		msg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "shutting down; resume with last_event_id")
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(changesWriteWait))
+	}
+	logFollowErr(r.Context(), "changes websocket", err)
+}
+
+// parseChangesQuery reads the resume point (header value, else
+// ?last_event_id=) and the ?types= filter.
+func parseChangesQuery(r *http.Request, lastEventID string) (int64, []string, error) {
+	q := r.URL.Query()
+	if lastEventID == "" {
+		lastEventID = q.Get("last_event_id")
+	}
+	var after int64
+	if lastEventID != "" {
+		var err error
+		after, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || after < 0 {
+			return 0, nil, errors.New("invalid last event id")
+		}
+	}
+
+	var types []string
+	if s := q.Get("types"); s != "" {
+		for _, t := range strings.Split(s, ",") {
+			t = strings.TrimSpace(t)
+			if !webhooks.ValidEventType(t) {
+				return 0, nil, fmt.Errorf("unknown event type %q (known: %s)", t, strings.Join(webhooks.AllEventTypes, ", "))
+			}
+			types = append(types, t)
+		}
+	}
+	return after, types, nil
+}
+
+// logFollowErr logs stream failures other than the client leaving, falling
+// behind or being disconnected for shutdown, all of which are routine.
+func logFollowErr(ctx context.Context, what string, err error) {
+	if err == nil || errors.Is(err, context.Canceled) ||
+		errors.Is(err, changes.ErrLagged) || errors.Is(err, changes.ErrClosed) {
+		return
+	}
+	slog.ErrorContext(ctx, what, "err", err)
+}
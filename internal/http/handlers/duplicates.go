@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/dedupe"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+const defaultDuplicateScore = 0.8
+
+// --------- Duplicates
+
+func (h *Handlers) PeopleDuplicates(w http.ResponseWriter, r *http.Request) {
+	minScore := defaultDuplicateScore
+	if s := r.URL.Query().Get("min_score"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 1 {
+			httputil.Error(w, http.StatusBadRequest, "min_score must be a number between 0 and 1")
+			return
+		}
+		minScore = v
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	// Only people sharing a surname key or an email with someone are loaded;
+	// nobody else can score as a duplicate.
+	people, err := h.a.Store.DuplicateCandidates(r.Context())
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list: %v", err)
+		return
+	}
+	cs := make([]*dedupe.Candidate, 0, len(people))
+	for _, p := range people {
+		cs = append(cs, dedupe.NewCandidate(p))
+	}
+	pairs := dedupe.FindPairs(cs, minScore)
+
+	out := []models.DuplicateCandidate{}
+	for i := page.Offset; i < len(pairs) && len(out) < page.Limit; i++ {
+		p := pairs[i]
+		out = append(out, models.DuplicateCandidate{A: p.A.Person, B: p.B.Person, Score: p.Score, Reasons: p.Reasons})
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, out)
+}
+
+func (h *Handlers) PeopleMerge(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	if id == otherID {
+		httputil.Error(w, http.StatusBadRequest, "cannot merge a person into themselves")
+		return
+	}
+
+	if err := h.a.Store.MergePeople(r.Context(), id, otherID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			httputil.Error(w, http.StatusNotFound, "not found")
+		case errors.Is(err, store.ErrInvalidRelation):
+			httputil.Error(w, http.StatusConflict, "cannot merge: %v", err)
+		default:
+			httputil.Error(w, http.StatusInternalServerError, "merge: %v", err)
+		}
+		return
+	}
+	p, err := h.a.Store.GetPersonWithDetails(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "get: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, p)
+}
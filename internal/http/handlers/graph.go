@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/graphexport"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultPathDepth    = 6
+	maxPathDepth        = 10
+	defaultNetworkDepth = 2
+	maxNetworkDepth     = 3
+)
+
+// --------- Graph
+
+func (h *Handlers) ShortestPath(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	maxDepth, err := queryInt(r, "max_depth", defaultPathDepth, 1, maxPathDepth)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if !h.peopleExist(w, r, id, otherID) {
+		return
+	}
+
+	ids, err := h.a.Store.ShortestPath(r.Context(), id, otherID, maxDepth)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "path: %v", err)
+		return
+	}
+	if ids == nil {
+		httputil.Error(w, http.StatusNotFound, "no path within %d hops", maxDepth)
+		return
+	}
+	people, err := h.a.Store.PeopleByIDs(r.Context(), ids)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "path people: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, models.Path{
+		From:    id,
+		To:      otherID,
+		Degrees: len(ids) - 1,
+		People:  people,
+	})
+}
+
+func (h *Handlers) Network(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	depth, err := queryInt(r, "depth", defaultNetworkDepth, 1, maxNetworkDepth)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if !h.peopleExist(w, r, id) {
+		return
+	}
+
+	out, err := h.a.Store.EgoNetwork(r.Context(), id, depth)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "network: %v", err)
+		return
+	}
+	out.Nodes = nonNil(out.Nodes)
+	out.Edges = nonNil(out.Edges)
+	httputil.JSON(w, http.StatusOK, out)
+}
+
+// GraphExport streams the friendship graph (or the ego network around ?ego=)
+// as JSON, GraphML, GEXF or DOT.
+func (h *Handlers) GraphExport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = graphexport.FormatJSON
+	}
+	filter := store.GraphFilter{
+		Nationality: strings.TrimSpace(q.Get("nationality")),
+		Gender:      strings.TrimSpace(q.Get("gender")),
+		LastName:    strings.TrimSpace(q.Get("last_name")),
+	}
+
+	var egoID int64
+	if s := q.Get("ego"); s != "" {
+		var err error
+		if egoID, err = parseID(s); err != nil {
+			httputil.Error(w, http.StatusBadRequest, "invalid ego: %v", err)
+			return
+		}
+		if filter != (store.GraphFilter{}) {
+			httputil.Error(w, http.StatusBadRequest, "ego cannot be combined with attribute filters")
+			return
+		}
+	}
+	depth, err := queryInt(r, "depth", defaultNetworkDepth, 1, maxNetworkDepth)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	enc, err := graphexport.NewEncoder(format, w)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	if egoID != 0 {
+		if !h.peopleExist(w, r, egoID) {
+			return
+		}
+		network, err := h.a.Store.EgoNetwork(r.Context(), egoID, depth)
+		if err != nil {
+			httputil.Error(w, http.StatusInternalServerError, "network: %v", err)
+			return
+		}
+		writeExportHeaders(w, format)
+		for _, n := range network.Nodes {
+			if err := enc.Node(n.Person); err != nil {
+				slog.ErrorContext(r.Context(), "graph export", "err", err)
+				return
+			}
+		}
+		for _, e := range network.Edges {
+			if err := enc.Edge(e); err != nil {
+				slog.ErrorContext(r.Context(), "graph export", "err", err)
+				return
+			}
+		}
+		if err := enc.Close(); err != nil {
+			slog.ErrorContext(r.Context(), "graph export", "err", err)
+		}
+		return
+	}
+
+	snap, err := h.a.Store.GraphSnapshot(r.Context())
+	if errors.Is(err, store.ErrTooManyExports) {
+		w.Header().Set("Retry-After", "30")
+		httputil.Error(w, http.StatusServiceUnavailable, "%v", err)
+		return
+	}
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "export: %v", err)
+		return
+	}
+	defer snap.Close()
+
+	// Headers go out with the first byte; after that errors can only be logged.
+	writeExportHeaders(w, format)
+	if err := snap.EachPerson(r.Context(), filter, enc.Node); err != nil {
+		slog.ErrorContext(r.Context(), "graph export nodes", "err", err)
+		return
+	}
+	if err := snap.EachFriendship(r.Context(), filter, enc.Edge); err != nil {
+		slog.ErrorContext(r.Context(), "graph export edges", "err", err)
+		return
+	}
+	if err := enc.Close(); err != nil {
+		slog.ErrorContext(r.Context(), "graph export", "err", err)
+	}
+}
+
+func writeExportHeaders(w http.ResponseWriter, format string) {
+	w.Header().Set("Content-Type", graphexport.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="people.%s"`, format))
+}
+
+// queryInt reads an optional integer query parameter bounded by [min, max].
+func queryInt(r *http.Request, name string, def, min, max int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be an integer between %d and %d", name, min, max)
+	}
+	return n, nil
+}
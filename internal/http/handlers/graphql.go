@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/gql"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+)
+
+// --------- GraphQL
+
+// GraphQL accepts POSTed JSON requests, and GET with ?query= for queries.
+// Query errors are reported in the body with status 200, as GraphQL clients
+// expect.
+func (h *Handlers) GraphQL(w http.ResponseWriter, r *http.Request) {
+	var req gql.Request
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		req.QueryOnly = true
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				httputil.Error(w, http.StatusBadRequest, "invalid variables: %v", err)
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid json: %v", err)
+		return
+	}
+	if req.Query == "" {
+		httputil.Error(w, http.StatusBadRequest, "query required")
+		return
+	}
+	httputil.JSON(w, http.StatusOK, h.a.GraphQL.Execute(r.Context(), req))
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/external/demographics"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/migrations"
+)
+
+// --------- Health
+
+const readyTimeout = 2 * time.Second
+
+type componentStatus struct {
+	Status string `json:"status"`
+	Detail any    `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status     string                     `json:"status"`
+	Components map[string]componentStatus `json:"components,omitempty"`
+}
+
+// Livez reports that the process is up; it never checks dependencies.
+func (h *Handlers) Livez(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// Readyz reports whether this replica should receive traffic: the database
+// answers and its schema is fully migrated. Provider circuits are listed but
+// an open circuit only degrades enrichment, so it does not fail readiness.
+func (h *Handlers) Readyz(w http.ResponseWriter, r *http.Request) {
+	if h.a.Draining.Load() {
+		httputil.JSON(w, http.StatusServiceUnavailable, readiness{Status: "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	out := readiness{Status: "ready", Components: map[string]componentStatus{}}
+	fail := func(name string, c componentStatus) {
+		c.Status = "fail"
+		out.Components[name] = c
+		out.Status = "not_ready"
+	}
+
+	start := time.Now()
+	if err := h.a.DB.PingContext(ctx); err != nil {
+		fail("database", componentStatus{Error: err.Error()})
+	} else {
+		out.Components["database"] = componentStatus{Status: "ok", Detail: map[string]any{"latency_ms": time.Since(start).Milliseconds()}}
+	}
+
+	version, dirty, err := h.a.Store.MigrationVersion(ctx)
+	want := migrations.Latest()
+	detail := map[string]any{"version": version, "dirty": dirty, "want": want}
+	switch {
+	case err != nil:
+		fail("migrations", componentStatus{Error: err.Error()})
+	case dirty || uint(version) < want:
+		fail("migrations", componentStatus{Detail: detail})
+	default:
+		out.Components["migrations"] = componentStatus{Status: "ok", Detail: detail}
+	}
+
+	for p, state := range h.a.Demographics.Circuits() {
+		c := componentStatus{Status: "ok", Detail: map[string]string{"circuit": state}}
+		if state != demographics.CircuitClosed {
+			c.Status = "degraded"
+		}
+		out.Components["demographics."+p] = c
+	}
+
+	code := http.StatusOK
+	if out.Status != "ready" {
+		code = http.StatusServiceUnavailable
+	}
+	httputil.JSON(w, code, out)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// --------- History
+
+func (h *Handlers) PersonHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	out, err := h.a.Store.PersonHistory(r.Context(), id, page.Limit, page.Offset)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "history: %v", err)
+		return
+	}
+	if len(out) == 0 && page.Offset == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+func (h *Handlers) RevertPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		httputil.Error(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	aff, err := h.a.Store.RevertPerson(r.Context(), id, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			httputil.Error(w, http.StatusNotFound, "version %d not found", version)
+		case errors.Is(err, store.ErrVersionNotRestorable):
+			httputil.Error(w, http.StatusUnprocessableEntity, "version %d is a deletion and cannot be restored", version)
+		default:
+			httputil.Error(w, http.StatusInternalServerError, "revert: %v", err)
+		}
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	p, err := h.a.Store.GetPersonWithDetails(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "get: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, p)
+}
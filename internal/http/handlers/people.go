@@ -7,10 +7,12 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Kirill-Pinyaev/people-api/internal/app"
 	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
 	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -37,19 +39,7 @@ func (h *Handlers) PeopleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	age, gender, nationality := req.Age, req.Gender, req.Nationality
-	if age == nil || gender == nil || nationality == nil {
-		a2, g2, n2 := h.a.Demographics.Infer(r.Context(), req.FirstName)
-		if age == nil {
-			age = a2
-		}
-		if gender == nil {
-			gender = g2
-		}
-		if nationality == nil {
-			nationality = n2
-		}
-	}
+	age, gender, nationality := h.a.Demographics.Complete(r.Context(), req.FirstName, req.Age, req.Gender, req.Nationality)
 
 	id, err := h.a.Store.InsertPerson(r.Context(),
 		req.FirstName, req.MiddleName, req.LastName, gender, nationality, age,
@@ -83,10 +73,25 @@ func (h *Handlers) PeopleGet(w http.ResponseWriter, r *http.Request) {
 		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
 		return
 	}
-	p, err := h.a.Store.GetPersonWithDetails(r.Context(), id)
+	var p models.Person
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		t, perr := time.Parse(time.RFC3339, asOf)
+		if perr != nil {
+			httputil.Error(w, http.StatusBadRequest, "invalid as_of: must be RFC 3339")
+			return
+		}
+		p, err = h.a.Store.GetPersonAsOf(r.Context(), id, t)
+	} else {
+		p, err = h.a.Store.GetPersonWithDetails(r.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			httputil.Error(w, http.StatusNotFound, "not found")
+			h.notFoundOrRedirect(w, r, id)
+			return
+		}
+		var noHist *store.NoHistoryError
+		if errors.As(err, &noHist) {
+			httputil.Error(w, http.StatusNotFound, "%v", noHist)
 			return
 		}
 		httputil.Error(w, http.StatusInternalServerError, "get: %v", err)
@@ -95,6 +100,25 @@ func (h *Handlers) PeopleGet(w http.ResponseWriter, r *http.Request) {
 	httputil.JSON(w, http.StatusOK, p)
 }
 
+// notFoundOrRedirect points clients at the survivor when id was merged away.
+func (h *Handlers) notFoundOrRedirect(w http.ResponseWriter, r *http.Request, id int64) {
+	to, err := h.a.Store.ResolveRedirect(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		httputil.Error(w, http.StatusInternalServerError, "resolve redirect: %v", err)
+		return
+	}
+	loc := "/v1/people/" + strconv.FormatInt(to, 10)
+	if r.URL.RawQuery != "" {
+		loc += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", loc)
+	httputil.Error(w, http.StatusPermanentRedirect, "person %d was merged into %d", id, to)
+}
+
 func (h *Handlers) PeopleList(w http.ResponseWriter, r *http.Request) {
 	out, err := h.a.Store.ListPeople(r.Context())
 	if err != nil {
@@ -151,6 +175,24 @@ func (h *Handlers) PeopleUpdate(w http.ResponseWriter, r *http.Request) {
 	httputil.JSON(w, http.StatusOK, p)
 }
 
+func (h *Handlers) PeopleDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	aff, err := h.a.Store.DeletePerson(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "delete: %v", err)
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // --------- Emails
 
 func (h *Handlers) AddEmail(w http.ResponseWriter, r *http.Request) {
@@ -239,6 +281,10 @@ func (h *Handlers) AddFriend(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.a.Store.AddFriend(r.Context(), id, friendID); err != nil {
+		if errors.Is(err, store.ErrBlocked) {
+			httputil.Error(w, http.StatusForbidden, "friendship blocked")
+			return
+		}
 		httputil.Error(w, http.StatusInternalServerError, "insert: %v", err)
 		return
 	}
@@ -282,6 +328,81 @@ func (h *Handlers) ListFriends(w http.ResponseWriter, r *http.Request) {
 	httputil.JSON(w, http.StatusOK, out)
 }
 
+func (h *Handlers) MutualFriends(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if !h.peopleExist(w, r, id, otherID) {
+		return
+	}
+	out, err := h.a.Store.MutualFriends(r.Context(), id, otherID, page.Limit, page.Offset)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "mutual friends: %v", err)
+		return
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+func (h *Handlers) FriendSuggestions(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if !h.peopleExist(w, r, id) {
+		return
+	}
+	out, err := h.a.Store.FriendSuggestions(r.Context(), id, page.Limit, page.Offset)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "friend suggestions: %v", err)
+		return
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+// peopleExist writes a 404 and returns false if any of ids is missing.
+func (h *Handlers) peopleExist(w http.ResponseWriter, r *http.Request, ids ...int64) bool {
+	for _, id := range ids {
+		ok, err := h.a.Store.PersonExists(r.Context(), id)
+		if err != nil {
+			httputil.Error(w, http.StatusInternalServerError, "lookup person: %v", err)
+			return false
+		}
+		if !ok {
+			httputil.Error(w, http.StatusNotFound, "person %d not found", id)
+			return false
+		}
+	}
+	return true
+}
+
+// nonNil makes empty result sets encode as [] instead of null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
 func parseID(s string) (int64, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/relations"
+	"github.com/Kirill-Pinyaev/people-api/internal/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// --------- Relations
+
+func (h *Handlers) ListRelations(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	var types []string
+	for _, v := range r.URL.Query()["type"] {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			if _, ok := relations.Lookup(t); !ok {
+				httputil.Error(w, http.StatusBadRequest, "unknown relation type %q (known: %s)", t, strings.Join(relations.Names(), ", "))
+				return
+			}
+			types = append(types, t)
+		}
+	}
+	out, err := h.a.Store.ListRelations(r.Context(), id, types)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list relations: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, out)
+}
+
+func (h *Handlers) AddRelation(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	var req models.CreateRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid json: %v", err)
+		return
+	}
+	t, ok := relations.Lookup(strings.ToLower(strings.TrimSpace(req.Type)))
+	if !ok {
+		httputil.Error(w, http.StatusBadRequest, "unknown relation type %q (known: %s)", req.Type, strings.Join(relations.Names(), ", "))
+		return
+	}
+	if req.PersonID <= 0 {
+		httputil.Error(w, http.StatusBadRequest, "person_id must be positive")
+		return
+	}
+	if req.PersonID == id {
+		httputil.Error(w, http.StatusBadRequest, "cannot relate a person to themselves")
+		return
+	}
+	if !h.peopleExist(w, r, id, req.PersonID) {
+		return
+	}
+
+	if err := h.a.Store.AddRelation(r.Context(), id, req.PersonID, t); err != nil {
+		switch {
+		case errors.Is(err, store.ErrBlocked):
+			httputil.Error(w, http.StatusForbidden, "friendship blocked")
+		case errors.Is(err, store.ErrInvalidRelation):
+			httputil.Error(w, http.StatusUnprocessableEntity, "%v", err)
+		default:
+			httputil.Error(w, http.StatusInternalServerError, "add relation: %v", err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handlers) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid id: %v", err)
+		return
+	}
+	t, ok := relations.Lookup(strings.ToLower(chi.URLParam(r, "type")))
+	if !ok {
+		httputil.Error(w, http.StatusBadRequest, "unknown relation type %q", chi.URLParam(r, "type"))
+		return
+	}
+	otherID, err := parseID(chi.URLParam(r, "other_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid other_id: %v", err)
+		return
+	}
+	aff, err := h.a.Store.RemoveRelation(r.Context(), id, otherID, t)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "delete: %v", err)
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/webhooks"
+	"github.com/go-chi/chi/v5"
+)
+
+// --------- Webhooks (admin)
+
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	out, err := h.a.Store.ListWebhooks(r.Context())
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+func (h *Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid json: %v", err)
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validateWebhook(req.URL, req.EventTypes); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "generate secret: %v", err)
+		return
+	}
+	sub, err := h.a.Store.CreateWebhook(r.Context(), req.URL, secret, req.EventTypes)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "insert: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusCreated, models.IssuedWebhookSubscription{WebhookSubscription: sub, Secret: secret})
+}
+
+func (h *Handlers) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid webhook_id: %v", err)
+		return
+	}
+	sub, err := h.a.Store.GetWebhook(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		httputil.Error(w, http.StatusInternalServerError, "get: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, sub)
+}
+
+func (h *Handlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid webhook_id: %v", err)
+		return
+	}
+	var req models.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid json: %v", err)
+		return
+	}
+	if req.URL != nil {
+		u := strings.TrimSpace(*req.URL)
+		req.URL = &u
+		if err := validateWebhookURL(u); err != nil {
+			httputil.Error(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(*req.EventTypes); err != nil {
+			httputil.Error(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+	}
+
+	sub, err := h.a.Store.UpdateWebhook(r.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		httputil.Error(w, http.StatusInternalServerError, "update: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusOK, sub)
+}
+
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid webhook_id: %v", err)
+		return
+	}
+	aff, err := h.a.Store.DeleteWebhook(r.Context(), id)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "delete: %v", err)
+		return
+	}
+	if aff == 0 {
+		httputil.Error(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookExists(w, r)
+	if !ok {
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "pending", "delivered", "dead":
+	default:
+		httputil.Error(w, http.StatusBadRequest, "status must be one of pending, delivered, dead")
+		return
+	}
+	page, err := httputil.ParsePage(r)
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	out, err := h.a.Store.ListWebhookDeliveries(r.Context(), id, status, page.Limit, page.Offset)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "list deliveries: %v", err)
+		return
+	}
+	httputil.SetNextLink(w, r, page, len(out))
+	httputil.JSON(w, http.StatusOK, nonNil(out))
+}
+
+func (h *Handlers) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid webhook_id: %v", err)
+		return
+	}
+	deliveryID, err := parseID(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid delivery_id: %v", err)
+		return
+	}
+	d, err := h.a.Store.ReplayWebhookDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return
+		}
+		httputil.Error(w, http.StatusInternalServerError, "replay: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusAccepted, d)
+}
+
+// ReplayWebhook requeues dead-lettered deliveries, or with ?since= every
+// event from that point on.
+func (h *Handlers) ReplayWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookExists(w, r)
+	if !ok {
+		return
+	}
+	var since *time.Time
+	if s := r.URL.Query().Get("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			httputil.Error(w, http.StatusBadRequest, "invalid since: must be RFC 3339")
+			return
+		}
+		since = &t
+	}
+	n, err := h.a.Store.ReplayWebhookDeliveries(r.Context(), id, since)
+	if err != nil {
+		httputil.Error(w, http.StatusInternalServerError, "replay: %v", err)
+		return
+	}
+	httputil.JSON(w, http.StatusAccepted, map[string]int64{"replayed": n})
+}
+
+// webhookExists parses {webhook_id} and writes 400/404 if it does not name a
+// subscription.
+func (h *Handlers) webhookExists(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := parseID(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		httputil.Error(w, http.StatusBadRequest, "invalid webhook_id: %v", err)
+		return 0, false
+	}
+	if _, err := h.a.Store.GetWebhook(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			httputil.Error(w, http.StatusNotFound, "not found")
+			return 0, false
+		}
+		httputil.Error(w, http.StatusInternalServerError, "get: %v", err)
+		return 0, false
+	}
+	return id, true
+}
+
+func validateWebhook(rawURL string, eventTypes []string) error {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return err
+	}
+	return validateEventTypes(eventTypes)
+}
+
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func validateEventTypes(types []string) error {
+	for _, t := range types {
+		if !webhooks.ValidEventType(t) {
+			return fmt.Errorf("unknown event type %q (known: %s)", t, strings.Join(webhooks.AllEventTypes, ", "))
+		}
+	}
+	return nil
+}
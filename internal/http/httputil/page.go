@@ -0,0 +1,57 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePage reads ?limit=&offset= from the query string. A limit above
+// MaxLimit is an error, as openapi.yaml declares, not silently lowered.
+func ParsePage(r *http.Request) (Page, error) {
+	p := Page{Limit: DefaultLimit}
+	q := r.URL.Query()
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return Page{}, errors.New("limit must be a positive integer")
+		}
+		if n > MaxLimit {
+			return Page{}, fmt.Errorf("limit must be at most %d", MaxLimit)
+		}
+		p.Limit = n
+	}
+	if s := q.Get("offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return Page{}, errors.New("offset must be a non-negative integer")
+		}
+		p.Offset = n
+	}
+	return p, nil
+}
+
+// SetNextLink advertises the next page via the Link header when the current
+// page came back full.
+func SetNextLink(w http.ResponseWriter, r *http.Request, p Page, n int) {
+	if n < p.Limit {
+		return
+	}
+	u := *r.URL
+	q := u.Query()
+	q.Set("limit", strconv.Itoa(p.Limit))
+	q.Set("offset", strconv.Itoa(p.Offset+p.Limit))
+	u.RawQuery = q.Encode()
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+}
@@ -0,0 +1,152 @@
+// Package idempotency makes POST endpoints safe to retry. The first response
+// for an Idempotency-Key is stored and replayed for later requests with the
+// same key and payload.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLen  = 255
+	maxBodyLen = 1 << 20
+)
+
+type Store interface {
+	ClaimIdempotencyKey(ctx context.Context, subject, key, requestHash string, lease time.Duration) (models.IdempotencyRecord, bool, error)
+	SaveIdempotentResponse(ctx context.Context, subject, key string, status int, contentType string, body []byte, ttl time.Duration) error
+	ReleaseIdempotencyKey(ctx context.Context, subject, key string) error
+}
+
+// Middleware must run after authentication: keys are scoped to the caller.
+// Requests without the header pass straight through. A key is held for
+// lease while its request runs, so that a retry is not locked out for long
+// if the process dies mid-request, and its response is kept for ttl.
+func Middleware(st Store, lease, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLen {
+				httputil.Error(w, http.StatusBadRequest, "%s must be at most %d characters", Header, maxKeyLen)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyLen+1))
+			if err != nil {
+				httputil.Error(w, http.StatusBadRequest, "read body: %v", err)
+				return
+			}
+			if len(body) > maxBodyLen {
+				httputil.Error(w, http.StatusRequestEntityTooLarge, "body too large for an idempotent request")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			subject := auth.Subject(r.Context())
+			hash := requestHash(r, body)
+
+			rec, claimed, err := st.ClaimIdempotencyKey(r.Context(), subject, key, hash, lease)
+			if err != nil {
+				httputil.Error(w, http.StatusInternalServerError, "idempotency: %v", err)
+				return
+			}
+			if !claimed {
+				replay(w, rec, hash)
+				return
+			}
+
+			rw := &recorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				// Detach from the request context: the client may already be
+				// gone, but the outcome still has to be recorded.
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+				defer cancel()
+				if p := recover(); p != nil {
+					_ = st.ReleaseIdempotencyKey(ctx, subject, key)
+					panic(p)
+				}
+				var err error
+				if rw.status >= 500 {
+					err = st.ReleaseIdempotencyKey(ctx, subject, key)
+				} else {
+					err = st.SaveIdempotentResponse(ctx, subject, key, rw.status, rw.Header().Get("Content-Type"), rw.body.Bytes(), ttl)
+				}
+				if err != nil {
+					slog.ErrorContext(ctx, "idempotency: save response", "key", key, "err", err)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, rec models.IdempotencyRecord, hash string) {
+	if rec.RequestHash != hash {
+		httputil.Error(w, http.StatusUnprocessableEntity, "%s was already used with a different request", Header)
+		return
+	}
+	if rec.Status == nil {
+		w.Header().Set("Retry-After", "1")
+		httputil.Error(w, http.StatusConflict, "a request with this %s is still in progress", Header)
+		return
+	}
+	if rec.ContentType != nil && *rec.ContentType != "" {
+		w.Header().Set("Content-Type", *rec.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(*rec.Status)
+	_, _ = w.Write(rec.Body)
+}
+
+// requestHash fingerprints what the key promises to repeat: the route and
+// the payload.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.Path)
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *recorder) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.status = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recorder) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -1,62 +1,178 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
-	"time"
 
+	peopleapi "github.com/Kirill-Pinyaev/people-api"
 	"github.com/Kirill-Pinyaev/people-api/internal/app"
+	"github.com/Kirill-Pinyaev/people-api/internal/auth"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/apidocs"
 	"github.com/Kirill-Pinyaev/people-api/internal/http/handlers"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/idempotency"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/validation"
+	"github.com/Kirill-Pinyaev/people-api/internal/logging"
+	"github.com/Kirill-Pinyaev/people-api/internal/metrics"
+	"github.com/Kirill-Pinyaev/people-api/internal/tracing"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-func New(a *app.App) http.Handler {
+func New(a *app.App) (http.Handler, error) {
+	r, err := Routes(a)
+	if err != nil {
+		return nil, err
+	}
+	// The server span wraps routing so that every middleware, including the
+	// access log, runs inside it.
+	return otelhttp.NewHandler(r, "http.server"), nil
+}
+
+// Routes builds the router without the outer tracing span, for callers that
+// walk the route table.
+func Routes(a *app.App) (*chi.Mux, error) {
+	requestTimeout := a.Config.HTTP.RequestTimeout
+
+	// Validation runs after authentication, so that a request without
+	// credentials gets 401 whatever its shape.
+	validate := func(next http.Handler) http.Handler { return next }
+	if o := (validation.Options{Requests: a.Config.HTTP.ValidateRequests, Responses: a.Config.HTTP.ValidateResponses}); o.Requests || o.Responses {
+		v, err := validation.New(peopleapi.OpenAPI, o)
+		if err != nil {
+			return nil, fmt.Errorf("openapi.yaml: %w", err)
+		}
+		validate = v.Middleware
+	}
+	docs, err := apidocs.New(peopleapi.OpenAPI)
+	if err != nil {
+		return nil, fmt.Errorf("openapi.yaml: %w", err)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(logging.Middleware(a.Logger))
+	r.Use(metrics.Middleware)
+	r.Use(tracing.RouteMiddleware)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(10 * time.Second))
 
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:8081", "http://127.0.0.1:8081"},
+		AllowedOrigins:   a.Config.HTTP.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Last-Event-ID", idempotency.Header},
+		ExposedHeaders:   []string{"Link", idempotency.ReplayedHeader},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 
 	h := handlers.New(a)
 
-	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("ok"))
-	})
+	r.Get("/healthz", h.Livez)
+	r.Get("/livez", h.Livez)
+	r.Get("/readyz", h.Readyz)
 
-	r.Get("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "openapi.yaml")
-	})
+	r.Handle("/metrics", metrics.Handler())
+
+	r.Get("/openapi.yaml", docs.YAML)
+	r.Get("/openapi.json", docs.JSON)
+	if a.Config.HTTP.DocsUI {
+		r.Handle(apidocs.UIPath+"*", docs.UI())
+		r.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, apidocs.UIPath, http.StatusMovedPermanently)
+		})
+	}
+
+	read := auth.Require(auth.ScopePeopleRead)
+	writePeople := auth.Require(auth.ScopePeopleWrite)
+	writeEmails := auth.Require(auth.ScopeEmailsWrite)
+	writeFriends := auth.Require(auth.ScopeFriendsWrite)
+	admin := auth.Require(auth.ScopeAdmin)
+	idem := idempotency.Middleware(a.Store, a.Config.HTTP.RequestTimeout, a.Config.HTTP.IdempotencyTTL)
 
 	r.Route("/v1", func(r chi.Router) {
-		r.Route("/people", func(r chi.Router) {
-			r.Get("/", h.PeopleList)
-			r.Post("/", h.PeopleCreate)
-			r.Get("/{id}", h.PeopleGet)
-			r.Patch("/{id}", h.PeopleUpdate)
+		r.Use(auth.Middleware(a.Auth))
+		r.Use(validate)
+
+		// Change feeds stay open indefinitely and graph exports stream for as
+		// long as the graph takes, so only the rest of the API gets the
+		// request timeout.
+		r.With(read).Get("/changes/stream", h.ChangesStream)
+		r.With(read).Get("/changes/ws", h.ChangesWebSocket)
+		r.With(read).Get("/graph:export", h.GraphExport)
 
-			r.Get("/surname/{last_name}", h.PeopleBySurname)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
 
-			r.Post("/{id}/emails", h.AddEmail)
-			r.Get("/{id}/emails", h.ListEmails)
-			r.Delete("/{id}/emails/{email_id}", h.DeleteEmail)
+			r.Route("/people", func(r chi.Router) {
+				r.With(read).Get("/", h.PeopleList)
+				r.With(writePeople, idem).Post("/", h.PeopleCreate)
+				r.With(read).Get("/{id}", h.PeopleGet)
+				r.With(writePeople).Patch("/{id}", h.PeopleUpdate)
+				r.With(writePeople).Delete("/{id}", h.PeopleDelete)
+				r.With(read).Get("/{id}/history", h.PersonHistory)
+				r.With(writePeople).Post("/{id}/revert/{version}", h.RevertPerson)
 
-			r.Post("/{id}/friends/{friend_id}", h.AddFriend)
-			r.Delete("/{id}/friends/{friend_id}", h.RemoveFriend)
-			r.Get("/{id}/friends", h.ListFriends)
+				r.With(read).Get("/surname/{last_name}", h.PeopleBySurname)
+				r.With(read).Get("/duplicates", h.PeopleDuplicates)
+				r.With(writePeople).Post("/{id}/merge/{other_id}", h.PeopleMerge)
+
+				r.With(writeEmails, idem).Post("/{id}/emails", h.AddEmail)
+				r.With(read).Get("/{id}/emails", h.ListEmails)
+				r.With(writeEmails).Delete("/{id}/emails/{email_id}", h.DeleteEmail)
+
+				r.With(writeFriends, idem).Post("/{id}/friends/{friend_id}", h.AddFriend)
+				r.With(writeFriends).Delete("/{id}/friends/{friend_id}", h.RemoveFriend)
+				r.With(read).Get("/{id}/friends", h.ListFriends)
+				r.With(read).Get("/{id}/friends/mutual/{other_id}", h.MutualFriends)
+				r.With(read).Get("/{id}/friend-suggestions", h.FriendSuggestions)
+
+				r.With(read).Get("/{id}/relations", h.ListRelations)
+				r.With(writeFriends).Post("/{id}/relations", h.AddRelation)
+				r.With(writeFriends).Delete("/{id}/relations/{type}/{other_id}", h.RemoveRelation)
+
+				r.With(read).Get("/{id}/blocks", h.ListBlocks)
+				r.With(writeFriends).Post("/{id}/blocks/{other_id}", h.BlockPerson)
+				r.With(writeFriends).Delete("/{id}/blocks/{other_id}", h.UnblockPerson)
+
+				r.With(read).Get("/{id}/path/{other_id}", h.ShortestPath)
+				r.With(read).Get("/{id}/network", h.Network)
+			})
+
+			r.With(admin).Get("/audit", h.ListAudit)
+
+			r.Route("/admin/api-keys", func(r chi.Router) {
+				r.Use(admin)
+				r.Get("/", h.ListAPIKeys)
+				r.Post("/", h.CreateAPIKey)
+				r.Post("/{key_id}/rotate", h.RotateAPIKey)
+				r.Delete("/{key_id}", h.RevokeAPIKey)
+			})
+
+			r.Route("/admin/webhooks", func(r chi.Router) {
+				r.Use(admin)
+				r.Get("/", h.ListWebhooks)
+				r.Post("/", h.CreateWebhook)
+				r.Get("/{webhook_id}", h.GetWebhook)
+				r.Patch("/{webhook_id}", h.UpdateWebhook)
+				r.Delete("/{webhook_id}", h.DeleteWebhook)
+				r.Get("/{webhook_id}/deliveries", h.ListWebhookDeliveries)
+				r.Post("/{webhook_id}/deliveries/{delivery_id}/replay", h.ReplayWebhookDelivery)
+				r.Post("/{webhook_id}/replay", h.ReplayWebhook)
+			})
 		})
 	})
 
-	return r
+	// Scopes are checked per field: queries need people:read, mutations the
+	// same scope as their REST counterpart.
+	r.Route("/graphql", func(r chi.Router) {
+		r.Use(auth.Middleware(a.Auth))
+		r.Use(validate)
+		r.Use(middleware.Timeout(requestTimeout))
+		r.Get("/", h.GraphQL)
+		r.Post("/", h.GraphQL)
+	})
+
+	return r, nil
 }
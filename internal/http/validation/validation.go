@@ -0,0 +1,221 @@
+// Package validation checks requests, and optionally responses, against
+// openapi.yaml. Routes the spec does not describe pass through unchecked.
+package validation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/http/httputil"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// maxResponseCopy bounds the response body kept for validation; larger
+// responses are not checked.
+const maxResponseCopy = 1 << 20
+
+func init() {
+	// kin-openapi only checks the formats it is told about.
+	openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+}
+
+// Options selects what is checked. Bad requests are rejected with a 400;
+// bad responses are only logged, never altered.
+type Options struct {
+	Requests  bool
+	Responses bool
+}
+
+type Validator struct {
+	router routers.Router
+	o      Options
+	opts   *openapi3filter.Options
+}
+
+// New loads and checks the spec.
+func New(spec []byte, o Options) (*Validator, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	// Match on paths only, whatever host the API is reached under.
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		router: router,
+		o:      o,
+		opts: &openapi3filter.Options{
+			// Authentication and scopes are checked by the auth middleware.
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			MultiError:         true,
+			// Handlers apply their own defaults to what the client sent.
+			SkipSettingDefaults: true,
+		},
+	}, nil
+}
+
+// FieldError is one reason a request was rejected. In is path, query,
+// header or body; Name is the parameter, or the JSON pointer into the body.
+type FieldError struct {
+	In     string `json:"in"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type errorResponse struct {
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details"`
+}
+
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := v.router.FindRoute(withoutTrailingSlash(r))
+		if err != nil {
+			// Unknown paths and methods are left to the router's 404 and 405.
+			next.ServeHTTP(w, r)
+			return
+		}
+		// The API only speaks JSON; clients that do not say so mean it.
+		if r.ContentLength != 0 && r.Header.Get("Content-Type") == "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		in := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options:    v.opts,
+		}
+		if v.o.Requests {
+			if err := openapi3filter.ValidateRequest(r.Context(), in); err != nil {
+				httputil.JSON(w, http.StatusBadRequest, errorResponse{
+					Error:   "bad request",
+					Message: "request does not match the API specification",
+					Details: fieldErrors(err),
+				})
+				return
+			}
+		}
+		// Upgraded connections need the original writer to hijack.
+		if !v.o.Responses || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.overflow || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+			return
+		}
+		out := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: in,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options:                v.opts,
+		}
+		if err := openapi3filter.ValidateResponse(r.Context(), out); err != nil {
+			// Only reasons are logged: the error text quotes the values,
+			// which may be personal data.
+			slog.ErrorContext(r.Context(), "response does not match the API specification",
+				"route", route.Path, "method", r.Method, "status", rec.status, "details", fieldErrors(err))
+		}
+	})
+}
+
+// withoutTrailingSlash lets "/v1/people/" match the spec's "/v1/people", as
+// the router does.
+func withoutTrailingSlash(r *http.Request) *http.Request {
+	p := r.URL.Path
+	if len(p) <= 1 || !strings.HasSuffix(p, "/") {
+		return r
+	}
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = strings.TrimRight(p, "/")
+	r2.URL.RawPath = ""
+	return r2
+}
+
+// fieldErrors flattens the validator's error tree into one entry per
+// failing parameter or body field, for requests and responses alike.
+func fieldErrors(err error) []FieldError {
+	var out []FieldError
+	var walk func(err error, in, name string)
+	walk = func(err error, in, name string) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, err := range e {
+				walk(err, in, name)
+			}
+		case *openapi3filter.RequestError:
+			switch {
+			case e.Parameter != nil:
+				in, name = e.Parameter.In, e.Parameter.Name
+			case e.RequestBody != nil:
+				in = "body"
+			}
+			if e.Err == nil {
+				out = append(out, FieldError{In: in, Name: name, Reason: e.Reason})
+				return
+			}
+			walk(e.Err, in, name)
+		case *openapi3filter.ResponseError:
+			if e.Err == nil {
+				out = append(out, FieldError{In: "body", Reason: e.Reason})
+				return
+			}
+			walk(e.Err, "body", name)
+		case *openapi3.SchemaError:
+			if in == "body" {
+				name = "/" + strings.Join(e.JSONPointer(), "/")
+			}
+			out = append(out, FieldError{In: in, Name: name, Reason: e.Reason})
+		case *openapi3filter.ParseError:
+			out = append(out, FieldError{In: in, Name: name, Reason: e.Error()})
+		default:
+			out = append(out, FieldError{In: in, Name: name, Reason: err.Error()})
+		}
+	}
+	walk(err, "", "")
+	return out
+}
+
+// recorder passes the response through while keeping a copy of the body.
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.overflow {
+		if r.body.Len()+len(b) > maxResponseCopy {
+			r.overflow = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
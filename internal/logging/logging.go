@@ -0,0 +1,115 @@
+// Package logging configures the process-wide slog logger and the access log.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// piiKeys are attribute keys whose values are replaced when redaction is on.
+var piiKeys = map[string]bool{
+	"name":        true,
+	"first_name":  true,
+	"middle_name": true,
+	"last_name":   true,
+	"email":       true,
+}
+
+const redacted = "[REDACTED]"
+
+type Options struct {
+	Level     string // debug, info, warn or error
+	Format    string // json or text
+	RedactPII bool
+}
+
+// New builds a logger writing to w. Every record logged with a context
+// carries that request's ID, trace ID and any fields added with Set.
+func New(w io.Writer, o Options) (*slog.Logger, error) {
+	var level slog.Level
+	if o.Level != "" {
+		if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+			return nil, fmt.Errorf("log level: %w", err)
+		}
+	}
+	ho := &slog.HandlerOptions{Level: level}
+	if o.RedactPII {
+		ho.ReplaceAttr = redact
+	}
+
+	var h slog.Handler
+	switch strings.ToLower(o.Format) {
+	case "", "json":
+		h = slog.NewJSONHandler(w, ho)
+	case "text":
+		h = slog.NewTextHandler(w, ho)
+	default:
+		return nil, fmt.Errorf("log format %q: want json or text", o.Format)
+	}
+	return slog.New(contextHandler{h}), nil
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if piiKeys[a.Key] {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+type fieldsKey struct{}
+
+type fields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// WithFields prepares ctx to collect request-scoped fields with Set.
+func WithFields(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &fields{})
+}
+
+// Set attaches attrs to every later record logged with ctx, including the
+// access log line. It is a no-op outside a request.
+func Set(ctx context.Context, attrs ...slog.Attr) {
+	f, ok := ctx.Value(fieldsKey{}).(*fields)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	f.attrs = append(f.attrs, attrs...)
+	f.mu.Unlock()
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := middleware.GetReqID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	if f, ok := ctx.Value(fieldsKey{}).(*fields); ok {
+		f.mu.Lock()
+		r.AddAttrs(f.attrs...)
+		f.mu.Unlock()
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware writes one access log line per request. It must run after
+// middleware.RequestID so the line carries the request ID.
+func Middleware(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := WithFields(r.Context())
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				level := slog.LevelInfo
+				switch {
+				case status >= 500:
+					level = slog.LevelError
+				case status >= 400:
+					level = slog.LevelWarn
+				}
+				// The raw path is left out: route params are logged by name
+				// instead so PII such as a surname can be redacted.
+				var route string
+				var params []any
+				if rc := chi.RouteContext(ctx); rc != nil {
+					route = rc.RoutePattern()
+					for i, k := range rc.URLParams.Keys {
+						if k == "*" {
+							continue
+						}
+						params = append(params, slog.String(k, rc.URLParams.Values[i]))
+					}
+				}
+				l.LogAttrs(ctx, level, "request",
+					slog.String("method", r.Method),
+					slog.String("route", route),
+					slog.Group("params", params...),
+					slog.Int("status", status),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.Duration("latency", time.Since(start)),
+					slog.String("remote_addr", r.RemoteAddr),
+				)
+			}()
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, the database
+// pool and the demographics providers.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "people"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, chi route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, chi route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served, including open change streams.",
+	})
+
+	providerRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "demographics_requests_total",
+		Help:      "Demographics provider requests by provider and outcome.",
+	}, []string{"provider", "outcome"})
+
+	providerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "demographics_request_duration_seconds",
+		Help:      "Demographics provider latency by provider and outcome.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2, 3, 5},
+	}, []string{"provider", "outcome"})
+
+	enrichmentsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "demographics_enrichments_in_flight",
+		Help:      "Person enrichments waiting on the providers.",
+	})
+)
+
+// Provider outcomes.
+const (
+	OutcomeOK        = "ok"
+	OutcomeBadStatus = "bad_status"
+	OutcomeTimeout   = "timeout"
+	OutcomeError     = "error"
+
+	// OutcomeCircuitOpen counts requests skipped by the circuit breaker.
+	OutcomeCircuitOpen = "circuit_open"
+)
+
+// Handler serves the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDB exports db.Stats() as go_sql_* gauges labelled db_name=name.
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Middleware counts requests and their latency. The route label is the chi
+// pattern, so it must run inside the chi router.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		httpInFlight.Inc()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			httpInFlight.Dec()
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := "unmatched"
+			if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePattern() != "" {
+				route = rc.RoutePattern()
+			}
+			labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+			httpRequests.With(labels).Inc()
+			httpDuration.With(labels).Observe(time.Since(start).Seconds())
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// ObserveProvider records one demographics provider request.
+func ObserveProvider(provider, outcome string, d time.Duration) {
+	providerRequests.WithLabelValues(provider, outcome).Inc()
+	providerDuration.WithLabelValues(provider, outcome).Observe(d.Seconds())
+}
+
+// EnrichmentStarted tracks an enrichment until the returned func is called.
+func EnrichmentStarted() (done func()) {
+	enrichmentsInFlight.Inc()
+	return enrichmentsInFlight.Dec
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Domain models
 
@@ -26,28 +29,192 @@ type Email struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type PersonVersion struct {
+	Version   int        `json:"version"`
+	Operation string     `json:"operation"`
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	Person    Person     `json:"person"`
+}
+
+type DuplicateCandidate struct {
+	A       Person   `json:"a"`
+	B       Person   `json:"b"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type FriendSuggestion struct {
+	Person
+	MutualFriends int     `json:"mutual_friends"`
+	Score         float64 `json:"score"`
+}
+
+type Friendship struct {
+	UserID    int64     `json:"user_id"`
+	FriendID  int64     `json:"friend_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Block struct {
+	BlockerID int64     `json:"blocker_id"`
+	BlockedID int64     `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Path struct {
+	From    int64    `json:"from"`
+	To      int64    `json:"to"`
+	Degrees int      `json:"degrees"`
+	People  []Person `json:"people"`
+}
+
+type NetworkNode struct {
+	Person
+	Depth int `json:"depth"`
+}
+
+type Network struct {
+	Center    int64         `json:"center"`
+	Depth     int           `json:"depth"`
+	Nodes     []NetworkNode `json:"nodes"`
+	Edges     []Friendship  `json:"edges"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+type Relation struct {
+	Type      string    `json:"type"`
+	Person    Person    `json:"person"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IdempotencyRecord is a stored response for an Idempotency-Key. Status is
+// nil while the original request is still in flight.
+type IdempotencyRecord struct {
+	RequestHash string
+	Status      *int
+	ContentType *string
+	Body        []byte
+}
+
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	KeyHash    string     `json:"-"`
+}
+
+// IssuedAPIKey carries the plaintext key; it is only ever returned once.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// OutboxEvent is a domain event as delivered to webhook subscribers.
+type OutboxEvent struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	EntityID   string          `json:"entity_id"`
+	RequestID  *string         `json:"request_id,omitempty"`
+	Data       json.RawMessage `json:"data"`
+	// TxID is the transaction that wrote the event; it orders the change
+	// feed.
+	TxID int64 `json:"-"`
+}
+
+// WebhookSubscription receives the event types it lists, or every type when
+// EventTypes is empty.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Secret     string    `json:"-"`
+}
+
+// IssuedWebhookSubscription carries the signing secret; it is only ever
+// returned on create.
+type IssuedWebhookSubscription struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	SubscriptionID int64      `json:"subscription_id"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatus     *int       `json:"last_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	RequestID  *string         `json:"request_id,omitempty"`
+	Entity     string          `json:"entity"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Diff       json.RawMessage `json:"diff"`
+}
+
 // Requests
 
 type CreatePersonRequest struct {
 	FirstName  string  `json:"first_name"`
-	MiddleName *string `json:"middle_name"`
+	MiddleName *string `json:"middle_name,omitempty"`
 	LastName   string  `json:"last_name"`
 
-	Gender      *string `json:"gender"`
-	Nationality *string `json:"nationality"`
-	Age         *int    `json:"age"`
+	Gender      *string `json:"gender,omitempty"`
+	Nationality *string `json:"nationality,omitempty"`
+	Age         *int    `json:"age,omitempty"`
 
 	Emails []struct {
 		Email     string `json:"email"`
 		IsPrimary bool   `json:"is_primary"`
-	} `json:"emails"`
+	} `json:"emails,omitempty"`
 }
 
 type UpdatePersonRequest struct {
-	FirstName   *string `json:"first_name"`
-	MiddleName  *string `json:"middle_name"`
-	LastName    *string `json:"last_name"`
-	Gender      *string `json:"gender"`
-	Nationality *string `json:"nationality"`
-	Age         *int    `json:"age"`
+	FirstName   *string `json:"first_name,omitempty"`
+	MiddleName  *string `json:"middle_name,omitempty"`
+	LastName    *string `json:"last_name,omitempty"`
+	Gender      *string `json:"gender,omitempty"`
+	Nationality *string `json:"nationality,omitempty"`
+	Age         *int    `json:"age,omitempty"`
+}
+
+type CreateRelationRequest struct {
+	Type     string `json:"type"`
+	PersonID int64  `json:"person_id"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url,omitempty"`
+	EventTypes *[]string `json:"event_types,omitempty"`
+	Active     *bool     `json:"active,omitempty"`
 }
@@ -0,0 +1,101 @@
+// Package relations describes the typed relationships people can have.
+//
+// A relation type names the role the other person plays for the subject:
+// "Anna is Ivan's parent" is the relation {type: parent, person: Anna} in
+// Ivan's list, and {type: child, person: Ivan} in Anna's. Every role maps to
+// a stored kind; directed kinds are persisted once, from the upper side
+// (parent, manager) to the lower side (child, report).
+package relations
+
+import (
+	"sort"
+)
+
+const (
+	Friend    = "friend"
+	Spouse    = "spouse"
+	Sibling   = "sibling"
+	Colleague = "colleague"
+	Parent    = "parent"
+	Child     = "child"
+	Manager   = "manager"
+	Report    = "report"
+)
+
+type Type struct {
+	Name    string
+	Inverse string
+	// Kind is what gets stored; for symmetric types it equals Name.
+	Kind      string
+	Symmetric bool
+	// Upper is true when the other person sits on the from side of the
+	// stored edge (the parent or manager of the subject).
+	Upper bool
+	// Acyclic kinds may not form a loop when followed from upper to lower.
+	Acyclic bool
+	// MaxUpper limits how many upper-side relations of this kind a person
+	// may have (two parents, one manager); MaxPerPerson limits symmetric
+	// kinds (one spouse). Zero means unlimited.
+	MaxUpper     int
+	MaxPerPerson int
+}
+
+var types = map[string]Type{
+	Friend:    {Name: Friend, Inverse: Friend, Kind: Friend, Symmetric: true},
+	Spouse:    {Name: Spouse, Inverse: Spouse, Kind: Spouse, Symmetric: true, MaxPerPerson: 1},
+	Sibling:   {Name: Sibling, Inverse: Sibling, Kind: Sibling, Symmetric: true},
+	Colleague: {Name: Colleague, Inverse: Colleague, Kind: Colleague, Symmetric: true},
+	Parent:    {Name: Parent, Inverse: Child, Kind: Parent, Upper: true, Acyclic: true, MaxUpper: 2},
+	Child:     {Name: Child, Inverse: Parent, Kind: Parent, Acyclic: true, MaxUpper: 2},
+	Manager:   {Name: Manager, Inverse: Report, Kind: Manager, Upper: true, Acyclic: true, MaxUpper: 1},
+	Report:    {Name: Report, Inverse: Manager, Kind: Manager, Acyclic: true, MaxUpper: 1},
+}
+
+func Lookup(name string) (Type, bool) {
+	t, ok := types[name]
+	return t, ok
+}
+
+// Names lists every known relation type, sorted.
+func Names() []string {
+	out := make([]string, 0, len(types))
+	for n := range types {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Edge orients a subject/other pair the way the kind is stored. Symmetric
+// kinds are stored with the smaller id first, mirroring friendships.
+func (t Type) Edge(subject, other int64) (from, to int64) {
+	switch {
+	case t.Symmetric:
+		if subject > other {
+			return other, subject
+		}
+		return subject, other
+	case t.Upper:
+		return other, subject
+	default:
+		return subject, other
+	}
+}
+
+// Role returns the type name the other end of a stored edge plays for
+// subject.
+func Role(kind string, from, subject int64) string {
+	switch kind {
+	case Parent:
+		if from == subject {
+			return Child
+		}
+		return Parent
+	case Manager:
+		if from == subject {
+			return Report
+		}
+		return Manager
+	}
+	return kind
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_at, rotated_at, last_used_at, revoked_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }) (models.APIKey, error) {
+	var k models.APIKey
+	var scopes string
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &scopes, &k.CreatedAt, &k.RotatedAt, &k.LastUsedAt, &k.RevokedAt)
+	k.Scopes = strings.Fields(scopes)
+	return k, err
+}
+
+func (s *Store) CreateAPIKey(ctx context.Context, name, prefix, hash string, scopes []string) (models.APIKey, error) {
+	var k models.APIKey
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		k, err = scanAPIKey(tx.QueryRowContext(ctx, `
+			INSERT INTO api_keys (name, prefix, key_hash, scopes) VALUES ($1,$2,$3,$4)
+			RETURNING `+apiKeyColumns, name, prefix, hash, strings.Join(scopes, " ")))
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityAPIKey, strconv.FormatInt(k.ID, 10), audit.ActionCreate, nil, k)
+	})
+	return k, err
+}
+
+func (s *Store) APIKeyByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys WHERE prefix=$1
+	`, prefix))
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// TouchAPIKey bumps last_used_at, at most once a minute per key to keep
+// authenticated reads from turning into a write each.
+func (s *Store) TouchAPIKey(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`, id)
+	return err
+}
+
+// RotateAPIKey swaps in a new secret for an active key. It returns
+// sql.ErrNoRows if the key does not exist or is revoked.
+func (s *Store) RotateAPIKey(ctx context.Context, id int64, prefix, hash string) (models.APIKey, error) {
+	return s.updateAPIKey(ctx, id, `prefix=$2, key_hash=$3, rotated_at=NOW()`, prefix, hash)
+}
+
+func (s *Store) RevokeAPIKey(ctx context.Context, id int64) (int64, error) {
+	_, err := s.updateAPIKey(ctx, id, `revoked_at=NOW()`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// updateAPIKey applies set to an active key and audits the change. Extra
+// args bind from $2 on.
+func (s *Store) updateAPIKey(ctx context.Context, id int64, set string, args ...any) (models.APIKey, error) {
+	var after models.APIKey
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		before, err := scanAPIKey(tx.QueryRowContext(ctx, `
+			SELECT `+apiKeyColumns+` FROM api_keys WHERE id=$1 AND revoked_at IS NULL FOR UPDATE
+		`, id))
+		if err != nil {
+			return err
+		}
+		after, err = scanAPIKey(tx.QueryRowContext(ctx, `
+			UPDATE api_keys SET `+set+` WHERE id=$1 RETURNING `+apiKeyColumns,
+			append([]any{id}, args...)...))
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityAPIKey, strconv.FormatInt(id, 10), audit.ActionUpdate, before, after)
+	})
+	return after, err
+}
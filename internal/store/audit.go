@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/webhooks"
+)
+
+// inTx runs fn in a transaction, committing if it returns nil.
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeAudit records a mutation inside the caller's transaction, attributed
+// to the actor and request found in ctx. Mutations that are part of the
+// public event stream are also written to the outbox.
+func writeAudit(ctx context.Context, tx *sql.Tx, entity, entityID, action string, before, after any) error {
+	diff, err := audit.Diff(before, after)
+	if err != nil {
+		return err
+	}
+	var reqID *string
+	if id := audit.RequestID(ctx); id != "" {
+		reqID = &id
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_events (actor, request_id, entity, entity_id, action, diff)
+		VALUES ($1,$2,$3,$4,$5,$6)
+	`, audit.Actor(ctx), reqID, entity, entityID, action, string(diff))
+	if err != nil {
+		return err
+	}
+
+	if t, ok := webhooks.EventType(entity, action); ok {
+		data := after
+		if action == audit.ActionDelete {
+			data = before
+		}
+		return writeEvent(ctx, tx, t, entityID, reqID, data)
+	}
+	return nil
+}
+
+type AuditFilter struct {
+	Entity   string
+	EntityID string
+	Actor    string
+}
+
+func (s *Store) ListAuditEvents(ctx context.Context, f AuditFilter, limit, offset int) ([]models.AuditEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, occurred_at, actor, request_id, entity, entity_id, action, diff
+		FROM audit_events
+		WHERE ($1 = '' OR entity = $1)
+		  AND ($2 = '' OR entity_id = $2)
+		  AND ($3 = '' OR actor = $3)
+		ORDER BY id DESC
+		LIMIT $4 OFFSET $5
+	`, f.Entity, f.EntityID, f.Actor, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		var diff []byte
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.RequestID, &e.Entity, &e.EntityID, &e.Action, &diff); err != nil {
+			return nil, err
+		}
+		e.Diff = diff
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+var ErrBlocked = errors.New("blocked")
+
+// blockedBetweenSQL matches a block in either direction between $1 and $2.
+const blockedBetweenSQL = `
+	SELECT 1 FROM blocks
+	WHERE (blocker_id=$1 AND blocked_id=$2) OR (blocker_id=$2 AND blocked_id=$1)`
+
+// blockedIDsSQL expands to everyone in a block relation with ph, whichever
+// side initiated it.
+func blockedIDsSQL(ph string) string {
+	return `SELECT blocked_id FROM blocks WHERE blocker_id=` + ph + `
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id=` + ph
+}
+
+// Block records that blocker blocks blocked and drops any friendship between
+// them in the same transaction.
+func (s *Store) Block(ctx context.Context, blocker, blocked int64) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := lockPair(ctx, tx, blocker, blocked); err != nil {
+			return err
+		}
+		var b models.Block
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1,$2) ON CONFLICT DO NOTHING
+			RETURNING blocker_id, blocked_id, created_at
+		`, blocker, blocked).Scan(&b.BlockerID, &b.BlockedID, &b.CreatedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// already blocked
+		case err != nil:
+			return err
+		default:
+			if err := writeAudit(ctx, tx, audit.EntityBlock, audit.PairID(blocker, blocked), audit.ActionCreate, nil, b); err != nil {
+				return err
+			}
+		}
+
+		u1, u2 := blocker, blocked
+		if u1 > u2 {
+			u1, u2 = u2, u1
+		}
+		_, err = deleteFriendship(ctx, tx, u1, u2)
+		return err
+	})
+}
+
+// deleteBlocksOf removes and audits every block id is on either side of.
+func deleteBlocksOf(ctx context.Context, tx *sql.Tx, id int64) ([]models.Block, error) {
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM blocks WHERE blocker_id=$1 OR blocked_id=$1
+		RETURNING blocker_id, blocked_id, created_at
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	var out []models.Block
+	for rows.Next() {
+		var b models.Block
+		if err := rows.Scan(&b.BlockerID, &b.BlockedID, &b.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, b := range out {
+		if err := writeAudit(ctx, tx, audit.EntityBlock, audit.PairID(b.BlockerID, b.BlockedID), audit.ActionDelete, b, nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) Unblock(ctx context.Context, blocker, blocked int64) (int64, error) {
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var b models.Block
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM blocks WHERE blocker_id=$1 AND blocked_id=$2
+			RETURNING blocker_id, blocked_id, created_at
+		`, blocker, blocked).Scan(&b.BlockerID, &b.BlockedID, &b.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityBlock, audit.PairID(blocker, blocked), audit.ActionDelete, b, nil)
+	})
+	return aff, err
+}
+
+// IsBlocked reports whether either person has blocked the other.
+func (s *Store) IsBlocked(ctx context.Context, a, b int64) (bool, error) {
+	var ok bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (`+blockedBetweenSQL+`)`, a, b).Scan(&ok)
+	return ok, err
+}
+
+// ListBlocked returns the people id has blocked.
+func (s *Store) ListBlocked(ctx context.Context, id int64) ([]models.Person, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at
+		FROM people p
+		JOIN blocks b ON b.blocked_id = p.id
+		WHERE b.blocker_id=$1
+		ORDER BY p.id ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPeople(rows)
+}
+
+// blockedSet returns everyone in a block relation with id.
+func (s *Store) blockedSet(ctx context.Context, id int64) (map[int64]bool, error) {
+	rows, err := s.db.QueryContext(ctx, blockedIDsSQL("$1"), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int64]bool)
+	for rows.Next() {
+		var bid int64
+		if err := rows.Scan(&bid); err != nil {
+			return nil, err
+		}
+		out[bid] = true
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,388 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+// MaxNetworkNodes caps how many people EgoNetwork collects before it stops
+// expanding further levels.
+const MaxNetworkNodes = 2000
+
+// ShortestPath runs a bidirectional BFS over friendships and returns the ids
+// on the shortest chain from -> to, or nil if none exists within maxDepth hops.
+// Chains never pass through anyone in a block relation with from.
+func (s *Store) ShortestPath(ctx context.Context, from, to int64, maxDepth int) ([]int64, error) {
+	if from == to {
+		return []int64{from}, nil
+	}
+
+	blocked, err := s.blockedSet(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	if blocked[to] {
+		return nil, nil
+	}
+
+	prevF := map[int64]int64{from: 0}
+	prevB := map[int64]int64{to: 0}
+	frontF, frontB := []int64{from}, []int64{to}
+
+	for hops := 0; hops < maxDepth && len(frontF) > 0 && len(frontB) > 0; hops++ {
+		forward := len(frontF) <= len(frontB)
+		front, prev, other := frontF, prevF, prevB
+		if !forward {
+			front, prev, other = frontB, prevB, prevF
+		}
+
+		adj, err := s.adjacency(ctx, front)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []int64
+		var meet int64
+		for _, u := range front {
+			for _, v := range adj[u] {
+				if _, seen := prev[v]; seen || blocked[v] {
+					continue
+				}
+				prev[v] = u
+				next = append(next, v)
+				if _, ok := other[v]; ok && meet == 0 {
+					meet = v
+				}
+			}
+		}
+		if meet != 0 {
+			return joinPath(prevF, prevB, meet), nil
+		}
+
+		if forward {
+			frontF = next
+		} else {
+			frontB = next
+		}
+	}
+	return nil, nil
+}
+
+func joinPath(prevF, prevB map[int64]int64, meet int64) []int64 {
+	var path []int64
+	for v := meet; v != 0; v = prevF[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for v := prevB[meet]; v != 0; v = prevB[v] {
+		path = append(path, v)
+	}
+	return path
+}
+
+// EgoNetwork collects everyone within depth hops of id together with the
+// friendships between them, leaving out anyone in a block relation with id.
+func (s *Store) EgoNetwork(ctx context.Context, id int64, depth int) (models.Network, error) {
+	out := models.Network{Center: id, Depth: depth}
+
+	blocked, err := s.blockedSet(ctx, id)
+	if err != nil {
+		return models.Network{}, err
+	}
+
+	dist := map[int64]int{id: 0}
+	order := []int64{id}
+	front := []int64{id}
+	for d := 1; d <= depth && len(front) > 0 && !out.Truncated; d++ {
+		adj, err := s.adjacency(ctx, front)
+		if err != nil {
+			return models.Network{}, err
+		}
+		var next []int64
+		for _, u := range front {
+			for _, v := range adj[u] {
+				if _, seen := dist[v]; seen || blocked[v] {
+					continue
+				}
+				if len(order) >= MaxNetworkNodes {
+					out.Truncated = true
+					break
+				}
+				dist[v] = d
+				order = append(order, v)
+				next = append(next, v)
+			}
+		}
+		front = next
+	}
+
+	people, err := s.peopleByIDs(ctx, order)
+	if err != nil {
+		return models.Network{}, err
+	}
+	for _, pid := range order {
+		p, ok := people[pid]
+		if !ok {
+			continue
+		}
+		out.Nodes = append(out.Nodes, models.NetworkNode{Person: p, Depth: dist[pid]})
+	}
+
+	out.Edges, err = s.edgesAmong(ctx, order)
+	if err != nil {
+		return models.Network{}, err
+	}
+	return out, nil
+}
+
+// PeopleByIDs returns the people for ids in the same order, skipping ids that
+// no longer exist.
+func (s *Store) PeopleByIDs(ctx context.Context, ids []int64) ([]models.Person, error) {
+	byID, err := s.peopleByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Person, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// adjacency returns the sorted friend ids of every id in ids.
+func (s *Store) adjacency(ctx context.Context, ids []int64) (map[int64][]int64, error) {
+	out := make(map[int64][]int64, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, friend_id FROM friendships WHERE user_id = ANY($1)
+		UNION ALL
+		SELECT friend_id, user_id FROM friendships WHERE friend_id = ANY($1)
+		ORDER BY 1, 2
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u, v int64
+		if err := rows.Scan(&u, &v); err != nil {
+			return nil, err
+		}
+		out[u] = append(out[u], v)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) peopleByIDs(ctx context.Context, ids []int64) (map[int64]models.Person, error) {
+	out := make(map[int64]models.Person, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, first_name, middle_name, last_name, gender, nationality, age, created_at, updated_at
+		FROM people WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	people, err := scanPeople(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range people {
+		out[p.ID] = p
+	}
+	return out, nil
+}
+
+func (s *Store) edgesAmong(ctx context.Context, ids []int64) ([]models.Friendship, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, friend_id, created_at
+		FROM friendships
+		WHERE user_id = ANY($1) AND friend_id = ANY($1)
+		ORDER BY user_id, friend_id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Friendship
+	for rows.Next() {
+		var f models.Friendship
+		if err := rows.Scan(&f.UserID, &f.FriendID, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// GraphFilter restricts people to those matching every non-empty field. In
+// graph exports, edges are kept only when both endpoints match.
+type GraphFilter struct {
+	Nationality string
+	Gender      string
+	LastName    string
+}
+
+func (f GraphFilter) where(alias string, args *[]any) string {
+	conds := []string{"TRUE"}
+	add := func(expr, v string) {
+		*args = append(*args, v)
+		conds = append(conds, fmt.Sprintf(expr, alias, len(*args)))
+	}
+	if f.Nationality != "" {
+		add("UPPER(%s.nationality) = UPPER($%d)", f.Nationality)
+	}
+	if f.Gender != "" {
+		add("LOWER(%s.gender) = LOWER($%d)", f.Gender)
+	}
+	if f.LastName != "" {
+		add("LOWER(%s.last_name) = LOWER($%d)", f.LastName)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// SearchPeople returns one page of matching people ordered by id.
+func (s *Store) SearchPeople(ctx context.Context, f GraphFilter, limit, offset int) ([]models.Person, error) {
+	var args []any
+	where := f.where("p", &args)
+	args = append(args, limit, offset)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at
+		FROM people p
+		WHERE %s
+		ORDER BY p.id ASC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPeople(rows)
+}
+
+// ErrTooManyExports is returned by GraphSnapshot while the maximum number
+// of snapshots set by LimitGraphExports are open.
+var ErrTooManyExports = errors.New("too many graph exports running")
+
+// LimitGraphExports caps how many graph snapshots may be open at once. Each
+// holds a connection for as long as its client takes to read the export, so
+// without a cap slow clients can drain the pool.
+func (s *Store) LimitGraphExports(n int) {
+	s.exports = make(chan struct{}, n)
+}
+
+// GraphSnapshot reads people and friendships as of one moment, so that
+// every friendship it returns joins two people it also returns. It holds a
+// connection until Close.
+type GraphSnapshot struct {
+	tx      *sql.Tx
+	release func()
+}
+
+func (s *Store) GraphSnapshot(ctx context.Context) (*GraphSnapshot, error) {
+	release := func() {}
+	if s.exports != nil {
+		select {
+		case s.exports <- struct{}{}:
+			release = func() { <-s.exports }
+		default:
+			return nil, ErrTooManyExports
+		}
+	}
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &GraphSnapshot{tx: tx, release: release}, nil
+}
+
+func (g *GraphSnapshot) Close() error {
+	defer g.release()
+	return g.tx.Rollback()
+}
+
+// EachPerson streams matching people ordered by id.
+func (g *GraphSnapshot) EachPerson(ctx context.Context, f GraphFilter, fn func(models.Person) error) error {
+	return eachPerson(ctx, g.tx, f, fn)
+}
+
+// EachPerson streams matching people ordered by id.
+func (s *Store) EachPerson(ctx context.Context, f GraphFilter, fn func(models.Person) error) error {
+	return eachPerson(ctx, s.db, f, fn)
+}
+
+func eachPerson(ctx context.Context, q querier, f GraphFilter, fn func(models.Person) error) error {
+	var args []any
+	rows, err := q.QueryContext(ctx, `
+		SELECT p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at
+		FROM people p
+		WHERE `+f.where("p", &args)+`
+		ORDER BY p.id ASC
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Person
+		if err := rows.Scan(&p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachFriendship streams friendships whose endpoints both match f.
+func (g *GraphSnapshot) EachFriendship(ctx context.Context, f GraphFilter, fn func(models.Friendship) error) error {
+	var args []any
+	whereA := f.where("a", &args)
+	whereB := f.where("b", &args)
+	rows, err := g.tx.QueryContext(ctx, `
+		SELECT fr.user_id, fr.friend_id, fr.created_at
+		FROM friendships fr
+		JOIN people a ON a.id = fr.user_id
+		JOIN people b ON b.id = fr.friend_id
+		WHERE `+whereA+` AND `+whereB+`
+		ORDER BY fr.user_id, fr.friend_id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fr models.Friendship
+		if err := rows.Scan(&fr.UserID, &fr.FriendID, &fr.CreatedAt); err != nil {
+			return err
+		}
+		if err := fn(fr); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/dedupe"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+// ErrVersionNotRestorable is returned when reverting to a delete marker.
+var ErrVersionNotRestorable = errors.New("version cannot be restored")
+
+const historyColumns = `version, op, valid_from, valid_to,
+	person_id, first_name, middle_name, last_name, gender, nationality, age, created_at, updated_at`
+
+func scanVersion(row interface{ Scan(...any) error }) (models.PersonVersion, error) {
+	var v models.PersonVersion
+	p := &v.Person
+	err := row.Scan(&v.Version, &v.Operation, &v.ValidFrom, &v.ValidTo,
+		&p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt)
+	return v, err
+}
+
+func (s *Store) PersonHistory(ctx context.Context, id int64, limit, offset int) ([]models.PersonVersion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+historyColumns+`
+		FROM people_history
+		WHERE person_id=$1
+		ORDER BY version ASC
+		LIMIT $2 OFFSET $3
+	`, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.PersonVersion
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// GetPersonAsOf reconstructs a person and their emails as they were at t.
+// It returns sql.ErrNoRows if the person did not exist then and a
+// *NoHistoryError if they did but history starts after t.
+func (s *Store) GetPersonAsOf(ctx context.Context, id int64, t time.Time) (models.Person, error) {
+	v, err := scanVersion(s.db.QueryRowContext(ctx, `
+		SELECT `+historyColumns+`
+		FROM people_history
+		WHERE person_id=$1 AND op <> 'delete'
+		  AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)
+	`, id, t))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Person{}, s.historyGap(ctx, id, t)
+	}
+	if err != nil {
+		return models.Person{}, err
+	}
+	p := v.Person
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT email_id, person_id, email, is_primary, created_at
+		FROM emails_history
+		WHERE person_id=$1
+		  AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2)
+		ORDER BY is_primary DESC, email_id ASC
+	`, id, t)
+	if err != nil {
+		return models.Person{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.Email
+		if err := rows.Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt); err != nil {
+			return models.Person{}, err
+		}
+		p.Emails = append(p.Emails, e)
+	}
+	return p, rows.Err()
+}
+
+// NoHistoryError reports that a person existed at the requested time but
+// their history only starts later, at Since.
+type NoHistoryError struct {
+	Since time.Time
+}
+
+func (e *NoHistoryError) Error() string {
+	return "no history before " + e.Since.UTC().Format(time.RFC3339)
+}
+
+// historyGap explains why no version of id covers t: a *NoHistoryError if
+// the person was created before their first recorded version and t falls
+// in between, sql.ErrNoRows otherwise.
+func (s *Store) historyGap(ctx context.Context, id int64, t time.Time) error {
+	var createdAt, since time.Time
+	err := s.db.QueryRowContext(ctx, `
+		SELECT created_at, valid_from FROM people_history
+		WHERE person_id=$1 ORDER BY version ASC LIMIT 1
+	`, id).Scan(&createdAt, &since)
+	if err != nil {
+		return err
+	}
+	if !t.Before(createdAt) && t.Before(since) {
+		return &NoHistoryError{Since: since}
+	}
+	return sql.ErrNoRows
+}
+
+// RevertPerson restores the person's fields from an earlier version; the
+// restore itself becomes a new version. Emails are left as they are. It
+// returns (0, nil) if the person does not exist and sql.ErrNoRows if the
+// version does not.
+func (s *Store) RevertPerson(ctx context.Context, id int64, version int) (int64, error) {
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		before, err := scanPerson(tx.QueryRowContext(ctx, `
+			SELECT `+personColumns+` FROM people WHERE id=$1 FOR UPDATE
+		`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		v, err := scanVersion(tx.QueryRowContext(ctx, `
+			SELECT `+historyColumns+` FROM people_history WHERE person_id=$1 AND version=$2
+		`, id, version))
+		if err != nil {
+			return err
+		}
+		if v.Operation == "delete" {
+			return ErrVersionNotRestorable
+		}
+
+		old := v.Person
+		after, err := scanPerson(tx.QueryRowContext(ctx, `
+			UPDATE people SET
+				first_name = $1, middle_name = $2, last_name = $3,
+				gender = $4, nationality = $5, age = $6,
+				dedupe_key = $8, updated_at = NOW()
+			WHERE id=$7
+			RETURNING `+personColumns,
+			old.FirstName, old.MiddleName, old.LastName, old.Gender, old.Nationality, old.Age, id,
+			dedupe.SurnameKey(old.LastName)))
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(id, 10), audit.ActionUpdate, before, after)
+	})
+	return aff, err
+}
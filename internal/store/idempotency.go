@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+// ClaimIdempotencyKey reserves (subject, key) for a new request for lease,
+// after which a retry may claim it again if no response was saved. If the
+// key is already taken and not expired, it returns the existing record
+// instead and claimed is false.
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, subject, key, requestHash string, lease time.Duration) (models.IdempotencyRecord, bool, error) {
+	var claimed bool
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (subject, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT (subject, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status = NULL, content_type = NULL, body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+		RETURNING TRUE
+	`, subject, key, requestHash, lease.Seconds()).Scan(&claimed)
+	if err == nil {
+		return models.IdempotencyRecord{RequestHash: requestHash}, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	var rec models.IdempotencyRecord
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, status, content_type, body
+		FROM idempotency_keys WHERE subject=$1 AND key=$2
+	`, subject, key).Scan(&rec.RequestHash, &rec.Status, &rec.ContentType, &rec.Body)
+	return rec, false, err
+}
+
+// SaveIdempotentResponse stores the response to replay and keeps it for
+// ttl.
+func (s *Store) SaveIdempotentResponse(ctx context.Context, subject, key string, status int, contentType string, body []byte, ttl time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status=$3, content_type=$4, body=$5,
+			expires_at = NOW() + make_interval(secs => $6)
+		WHERE subject=$1 AND key=$2
+	`, subject, key, status, contentType, body, ttl.Seconds())
+	return err
+}
+
+// ReleaseIdempotencyKey forgets a claim whose request failed, so a retry can
+// run again instead of replaying the failure.
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, subject, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE subject=$1 AND key=$2 AND status IS NULL
+	`, subject, key)
+	return err
+}
+
+func (s *Store) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,339 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/relations"
+)
+
+// MergePeople folds merged into survivor: emails, friendships, relations and
+// blocks move over (duplicates and self-loops collapse), survivor's empty
+// fields are filled from merged, merged is deleted and a redirect is left
+// behind. Every moved row is audited like the single-entity endpoints do.
+// It returns sql.ErrNoRows if either person is missing, and wraps
+// ErrInvalidRelation if the moved relations would break a relation limit or
+// close a cycle.
+func (s *Store) MergePeople(ctx context.Context, survivor, merged int64) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		// Relation kinds are locked before the people rows: AddRelation holds
+		// its kind's lock while its insert waits on the people rows.
+		for _, kind := range relationKinds() {
+			if err := lockRelationKind(ctx, tx, kind); err != nil {
+				return err
+			}
+		}
+
+		// Lock both rows in id order so concurrent merges cannot deadlock.
+		lo, hi := survivor, merged
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		var locked int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM (SELECT id FROM people WHERE id IN ($1,$2) ORDER BY id FOR UPDATE) l
+		`, lo, hi).Scan(&locked); err != nil {
+			return err
+		}
+		if locked != 2 {
+			return sql.ErrNoRows
+		}
+
+		before, err := scanPerson(tx.QueryRowContext(ctx, `SELECT `+personColumns+` FROM people WHERE id=$1`, survivor))
+		if err != nil {
+			return err
+		}
+		gone, err := scanPerson(tx.QueryRowContext(ctx, `SELECT `+personColumns+` FROM people WHERE id=$1`, merged))
+		if err != nil {
+			return err
+		}
+
+		// Survivor keeps its own values; merged only fills the gaps.
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE people s SET
+				middle_name = COALESCE(s.middle_name, m.middle_name),
+				gender = COALESCE(s.gender, m.gender),
+				nationality = COALESCE(s.nationality, m.nationality),
+				age = COALESCE(s.age, m.age),
+				updated_at = NOW()
+			FROM people m
+			WHERE s.id=$1 AND m.id=$2
+		`, survivor, merged); err != nil {
+			return err
+		}
+
+		m := merge{tx: tx, survivor: survivor, merged: merged}
+		// Blocks move before friendships so that a carried-over block
+		// excludes the friendship it would otherwise sit next to.
+		for _, step := range []func(context.Context) error{m.emails, m.blocks, m.friendships, m.relations} {
+			if err := step(ctx); err != nil {
+				return err
+			}
+		}
+
+		// Redirects that pointed at merged now point at survivor.
+		if _, err := tx.ExecContext(ctx, `UPDATE person_redirects SET to_id=$1 WHERE to_id=$2`, survivor, merged); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM people WHERE id=$1`, merged); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO person_redirects (from_id, to_id) VALUES ($2,$1)
+			ON CONFLICT (from_id) DO UPDATE SET to_id=EXCLUDED.to_id, merged_at=NOW()
+		`, survivor, merged); err != nil {
+			return err
+		}
+
+		after, err := scanPerson(tx.QueryRowContext(ctx, `SELECT `+personColumns+` FROM people WHERE id=$1`, survivor))
+		if err != nil {
+			return err
+		}
+		if err := writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(survivor, 10), audit.ActionUpdate, before, after); err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(merged, 10), audit.ActionDelete, gone, nil)
+	})
+}
+
+// merge moves merged's rows over to survivor. Edges are deleted and
+// re-created, since their ids are the pair they join; emails keep theirs.
+type merge struct {
+	tx               *sql.Tx
+	survivor, merged int64
+}
+
+// remap replaces merged with survivor.
+func (m merge) remap(id int64) int64 {
+	if id == m.merged {
+		return m.survivor
+	}
+	return id
+}
+
+// emails hands merged's emails to survivor. Only one primary survives,
+// survivor's if it has one.
+func (m merge) emails(ctx context.Context) error {
+	rows, err := m.tx.QueryContext(ctx, `
+		SELECT id, person_id, email, is_primary, created_at FROM emails WHERE person_id=$1 ORDER BY id
+	`, m.merged)
+	if err != nil {
+		return err
+	}
+	var moved []models.Email
+	for rows.Next() {
+		var e models.Email
+		if err := rows.Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		moved = append(moved, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var hasPrimary bool
+	if err := m.tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM emails WHERE person_id=$1 AND is_primary)
+	`, m.survivor).Scan(&hasPrimary); err != nil {
+		return err
+	}
+	for _, e := range moved {
+		after := e
+		after.PersonID = m.survivor
+		after.IsPrimary = e.IsPrimary && !hasPrimary
+		if _, err := m.tx.ExecContext(ctx, `
+			UPDATE emails SET person_id=$2, is_primary=$3 WHERE id=$1
+		`, e.ID, after.PersonID, after.IsPrimary); err != nil {
+			return err
+		}
+		if err := writeAudit(ctx, m.tx, audit.EntityEmail, strconv.FormatInt(e.ID, 10), audit.ActionUpdate, e, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blocks carries merged's blocks over and, as Block does, drops any
+// friendship survivor has with the other side.
+func (m merge) blocks(ctx context.Context) error {
+	old, err := deleteBlocksOf(ctx, m.tx, m.merged)
+	if err != nil {
+		return err
+	}
+	for _, b := range old {
+		blocker, blocked := m.remap(b.BlockerID), m.remap(b.BlockedID)
+		if blocker == blocked {
+			continue
+		}
+		var nb models.Block
+		err := m.tx.QueryRowContext(ctx, `
+			INSERT INTO blocks (blocker_id, blocked_id, created_at) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING
+			RETURNING blocker_id, blocked_id, created_at
+		`, blocker, blocked, b.CreatedAt).Scan(&nb.BlockerID, &nb.BlockedID, &nb.CreatedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// survivor already had this block
+		case err != nil:
+			return err
+		default:
+			if err := writeAudit(ctx, m.tx, audit.EntityBlock, audit.PairID(blocker, blocked), audit.ActionCreate, nil, nb); err != nil {
+				return err
+			}
+		}
+		if _, err := deleteFriendship(ctx, m.tx, min(blocker, blocked), max(blocker, blocked)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// friendships carries merged's friendships over, skipping anyone survivor
+// is now in a block with.
+func (m merge) friendships(ctx context.Context) error {
+	old, err := deleteFriendshipsOf(ctx, m.tx, m.merged)
+	if err != nil {
+		return err
+	}
+	for _, f := range old {
+		a, b := m.remap(f.UserID), m.remap(f.FriendID)
+		if a == b {
+			continue
+		}
+		u1, u2 := min(a, b), max(a, b)
+		var nf models.Friendship
+		err := m.tx.QueryRowContext(ctx, `
+			INSERT INTO friendships (user_id, friend_id, created_at)
+			SELECT $1::bigint, $2::bigint, $3::timestamptz
+			WHERE NOT EXISTS (`+blockedBetweenSQL+`)
+			ON CONFLICT DO NOTHING
+			RETURNING user_id, friend_id, created_at
+		`, u1, u2, f.CreatedAt).Scan(&nf.UserID, &nf.FriendID, &nf.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := writeAudit(ctx, m.tx, audit.EntityFriendship, audit.PairID(u1, u2), audit.ActionCreate, nil, nf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relations carries merged's typed relations over under the same limit and
+// cycle checks as AddRelation; the kinds are already locked.
+func (m merge) relations(ctx context.Context) error {
+	old, err := deleteRelationsOf(ctx, m.tx, m.merged)
+	if err != nil {
+		return err
+	}
+	for _, r := range old {
+		t, ok := relations.Lookup(r.Kind)
+		if !ok {
+			return fmt.Errorf("unknown relation kind %q", r.Kind)
+		}
+		from, to := m.remap(r.FromID), m.remap(r.ToID)
+		if from == to {
+			continue
+		}
+		if t.Symmetric {
+			from, to = min(from, to), max(from, to)
+		}
+		exists, err := checkRelation(ctx, m.tx, from, to, t)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := insertRelation(ctx, m.tx, relationRow{FromID: from, ToID: to, Kind: r.Kind, CreatedAt: r.CreatedAt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relationKinds lists the stored relation kinds other than friendship,
+// sorted so that every merge takes their locks in the same order.
+func relationKinds() []string {
+	var out []string
+	for _, name := range relations.Names() {
+		t, _ := relations.Lookup(name)
+		if t.Kind != relations.Friend && !slices.Contains(out, t.Kind) {
+			out = append(out, t.Kind)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// ResolveRedirect returns the id a merged person now lives under, or
+// sql.ErrNoRows if id was never merged.
+func (s *Store) ResolveRedirect(ctx context.Context, id int64) (int64, error) {
+	var to int64
+	err := s.db.QueryRowContext(ctx, `SELECT to_id FROM person_redirects WHERE from_id=$1`, id).Scan(&to)
+	return to, err
+}
+
+// DuplicateCandidates returns, with their emails, the people who share a
+// surname key (people.dedupe_key) or an email with someone else: the only
+// people dedupe.FindPairs can pair up.
+func (s *Store) DuplicateCandidates(ctx context.Context) ([]models.Person, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+personColumns+` FROM people WHERE id IN (
+			SELECT id FROM people WHERE dedupe_key IN (
+				SELECT dedupe_key FROM people GROUP BY dedupe_key HAVING COUNT(*) > 1)
+			UNION
+			SELECT person_id FROM emails WHERE lower(trim(email)) IN (
+				SELECT lower(trim(email)) FROM emails GROUP BY 1 HAVING COUNT(DISTINCT person_id) > 1)
+		)
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	people, err := scanPeople(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(people))
+	for i, p := range people {
+		ids[i] = p.ID
+	}
+	erows, err := s.db.QueryContext(ctx, `
+		SELECT id, person_id, email, is_primary, created_at FROM emails
+		WHERE person_id = ANY($1)
+		ORDER BY is_primary DESC, id ASC
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer erows.Close()
+	emails := make(map[int64][]models.Email)
+	for erows.Next() {
+		var e models.Email
+		if err := erows.Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		emails[e.PersonID] = append(emails[e.PersonID], e)
+	}
+	if err := erows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range people {
+		people[i].Emails = emails[people[i].ID]
+	}
+	return people, nil
+}
@@ -0,0 +1,243 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/relations"
+)
+
+var ErrInvalidRelation = errors.New("invalid relation")
+
+// AddRelation records that other plays role t for subject. Friend relations
+// go through AddFriend so blocking rules still apply.
+func (s *Store) AddRelation(ctx context.Context, subject, other int64, t relations.Type) error {
+	if t.Kind == relations.Friend {
+		return s.AddFriend(ctx, subject, other)
+	}
+	from, to := t.Edge(subject, other)
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		// Serialise writers per kind so the limit and cycle checks below see
+		// a stable graph.
+		if err := lockRelationKind(ctx, tx, t.Kind); err != nil {
+			return err
+		}
+		exists, err := checkRelation(ctx, tx, from, to, t)
+		if err != nil || exists {
+			return err
+		}
+		return insertRelation(ctx, tx, relationRow{FromID: from, ToID: to, Kind: t.Kind})
+	})
+}
+
+func lockRelationKind(ctx context.Context, tx *sql.Tx, kind string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('relations:' || $1::text))`, kind)
+	return err
+}
+
+// checkRelation reports whether the edge already exists, or wraps
+// ErrInvalidRelation if adding it would break a limit or close a cycle. The
+// caller holds the kind's lock.
+func checkRelation(ctx context.Context, tx *sql.Tx, from, to int64, t relations.Type) (bool, error) {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM relations WHERE from_id=$1 AND to_id=$2 AND kind=$3)
+	`, from, to, t.Kind).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return true, nil
+	}
+
+	if t.MaxPerPerson > 0 {
+		for _, pid := range []int64{from, to} {
+			var n int
+			if err := tx.QueryRowContext(ctx, `
+				SELECT COUNT(*) FROM relations WHERE kind=$1 AND (from_id=$2 OR to_id=$2)
+			`, t.Kind, pid).Scan(&n); err != nil {
+				return false, err
+			}
+			if n >= t.MaxPerPerson {
+				return false, fmt.Errorf("%w: person %d already has a %s", ErrInvalidRelation, pid, t.Kind)
+			}
+		}
+	}
+
+	if t.MaxUpper > 0 {
+		var n int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM relations WHERE kind=$1 AND to_id=$2
+		`, t.Kind, to).Scan(&n); err != nil {
+			return false, err
+		}
+		if n >= t.MaxUpper {
+			return false, fmt.Errorf("%w: person %d already has %d %s relation(s)", ErrInvalidRelation, to, n, t.Kind)
+		}
+	}
+
+	if t.Acyclic {
+		// from -> to closes a loop if to is already above from.
+		var cycle bool
+		if err := tx.QueryRowContext(ctx, `
+			WITH RECURSIVE up(id) AS (
+				SELECT from_id FROM relations WHERE kind=$1 AND to_id=$2
+				UNION
+				SELECT r.from_id FROM relations r JOIN up ON r.to_id = up.id WHERE r.kind=$1
+			)
+			SELECT EXISTS (SELECT 1 FROM up WHERE id=$3)
+		`, t.Kind, from, to).Scan(&cycle); err != nil {
+			return false, err
+		}
+		if cycle {
+			return false, fmt.Errorf("%w: %s relation between %d and %d would create a cycle", ErrInvalidRelation, t.Kind, from, to)
+		}
+	}
+	return false, nil
+}
+
+// insertRelation stores and audits r; a zero CreatedAt means now.
+func insertRelation(ctx context.Context, tx *sql.Tx, r relationRow) error {
+	var createdAt *time.Time
+	if !r.CreatedAt.IsZero() {
+		createdAt = &r.CreatedAt
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO relations (from_id, to_id, kind, created_at) VALUES ($1,$2,$3,COALESCE($4,NOW()))
+		RETURNING from_id, to_id, kind, created_at
+	`, r.FromID, r.ToID, r.Kind, createdAt).Scan(&r.FromID, &r.ToID, &r.Kind, &r.CreatedAt); err != nil {
+		return err
+	}
+	return writeAudit(ctx, tx, audit.EntityRelation, r.auditID(), audit.ActionCreate, nil, r)
+}
+
+func (s *Store) RemoveRelation(ctx context.Context, subject, other int64, t relations.Type) (int64, error) {
+	if t.Kind == relations.Friend {
+		return s.RemoveFriend(ctx, subject, other)
+	}
+	from, to := t.Edge(subject, other)
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var row relationRow
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM relations WHERE from_id=$1 AND to_id=$2 AND kind=$3
+			RETURNING from_id, to_id, kind, created_at
+		`, from, to, t.Kind).Scan(&row.FromID, &row.ToID, &row.Kind, &row.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityRelation, row.auditID(), audit.ActionDelete, row, nil)
+	})
+	return aff, err
+}
+
+// deleteRelationsOf removes and audits every relation id is on either side
+// of.
+func deleteRelationsOf(ctx context.Context, tx *sql.Tx, id int64) ([]relationRow, error) {
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM relations WHERE from_id=$1 OR to_id=$1
+		RETURNING from_id, to_id, kind, created_at
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	var out []relationRow
+	for rows.Next() {
+		var r relationRow
+		if err := rows.Scan(&r.FromID, &r.ToID, &r.Kind, &r.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, r := range out {
+		if err := writeAudit(ctx, tx, audit.EntityRelation, r.auditID(), audit.ActionDelete, r, nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// relationRow is a stored relations edge as it appears in audit diffs.
+type relationRow struct {
+	FromID    int64     `json:"from_id"`
+	ToID      int64     `json:"to_id"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r relationRow) auditID() string {
+	return r.Kind + ":" + audit.PairID(r.FromID, r.ToID)
+}
+
+// ListRelations returns every relation of id, friendships included, seen
+// from id's side. A non-empty types restricts the result to those roles.
+func (s *Store) ListRelations(ctx context.Context, id int64, types []string) ([]models.Relation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT 'friend', user_id, friend_id, created_at FROM friendships WHERE user_id=$1 OR friend_id=$1
+		UNION ALL
+		SELECT kind, from_id, to_id, created_at FROM relations WHERE from_id=$1 OR to_id=$1
+		ORDER BY 4, 2, 3
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	want := make(map[string]bool, len(types))
+	for _, t := range types {
+		want[t] = true
+	}
+
+	type edge struct {
+		role      string
+		other     int64
+		createdAt time.Time
+	}
+	var edges []edge
+	var ids []int64
+	for rows.Next() {
+		var kind string
+		var from, to int64
+		var createdAt time.Time
+		if err := rows.Scan(&kind, &from, &to, &createdAt); err != nil {
+			return nil, err
+		}
+		role := relations.Role(kind, from, id)
+		if len(want) > 0 && !want[role] {
+			continue
+		}
+		other := from
+		if from == id {
+			other = to
+		}
+		edges = append(edges, edge{role: role, other: other, createdAt: createdAt})
+		ids = append(ids, other)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	people, err := s.peopleByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Relation, 0, len(edges))
+	for _, e := range edges {
+		out = append(out, models.Relation{Type: e.role, Person: people[e.other], CreatedAt: e.createdAt})
+	}
+	return out, nil
+}
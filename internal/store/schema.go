@@ -0,0 +1,10 @@
+package store
+
+import "context"
+
+// MigrationVersion reads the version recorded by golang-migrate. dirty is
+// true while a migration failed half way.
+func (s *Store) MigrationVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	err = s.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	return version, dirty, err
+}
@@ -3,16 +3,24 @@ package store
 import (
 	"context"
 	"database/sql"
-	"strings"
+	"errors"
 
-	"fmt"
 	"strconv"
 
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/dedupe"
 	"github.com/Kirill-Pinyaev/people-api/internal/models"
 )
 
 type Store struct {
 	db *sql.DB
+	// exports bounds open graph snapshots; see LimitGraphExports.
+	exports chan struct{}
+}
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 }
 
 func New(db *sql.DB) *Store {
@@ -21,16 +29,31 @@ func New(db *sql.DB) *Store {
 
 // ---------- people
 
+const personColumns = `id, first_name, middle_name, last_name, gender, nationality, age, created_at, updated_at`
+
+func scanPerson(row interface{ Scan(...any) error }) (models.Person, error) {
+	var p models.Person
+	err := row.Scan(&p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
 func (s *Store) InsertPerson(ctx context.Context,
 	firstName string, middleName *string, lastName string,
 	gender *string, nationality *string, age *int,
 ) (int64, error) {
-	var id int64
-	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO people (first_name, middle_name, last_name, gender, nationality, age)
-		VALUES ($1,$2,$3,$4,$5,$6) RETURNING id
-	`, firstName, middleName, lastName, gender, nationality, age).Scan(&id)
-	return id, err
+	var p models.Person
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		p, err = scanPerson(tx.QueryRowContext(ctx, `
+			INSERT INTO people (first_name, middle_name, last_name, gender, nationality, age, dedupe_key)
+			VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING `+personColumns,
+			firstName, middleName, lastName, gender, nationality, age, dedupe.SurnameKey(lastName)))
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(p.ID, 10), audit.ActionCreate, nil, p)
+	})
+	return p.ID, err
 }
 
 func (s *Store) GetPersonWithDetails(ctx context.Context, id int64) (models.Person, error) {
@@ -94,7 +117,7 @@ func (s *Store) ListPeople(ctx context.Context) ([]models.Person, error) {
 		return nil, err
 	}
 
-	emailsByPerson, err := s.emailsByPersonIDs(ctx, ids)
+	emailsByPerson, err := s.EmailsByPersonIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +149,7 @@ func (s *Store) ListBySurname(ctx context.Context, lastName string) ([]models.Pe
 		out = append(out, p)
 		ids = append(ids, p.ID)
 	}
-	emailsByPerson, err := s.emailsByPersonIDs(ctx, ids)
+	emailsByPerson, err := s.EmailsByPersonIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -137,31 +160,120 @@ func (s *Store) ListBySurname(ctx context.Context, lastName string) ([]models.Pe
 }
 
 func (s *Store) UpdatePerson(ctx context.Context, id int64, req models.UpdatePersonRequest) (int64, error) {
-	res, err := s.db.ExecContext(ctx, `
-		UPDATE people SET
-			first_name = COALESCE($1, first_name),
-			middle_name = COALESCE($2, middle_name),
-			last_name = COALESCE($3, last_name),
-			gender = COALESCE($4, gender),
-			nationality = COALESCE($5, nationality),
-			age = COALESCE($6, age),
-			updated_at = NOW()
-		WHERE id=$7
-	`, req.FirstName, req.MiddleName, req.LastName, req.Gender, req.Nationality, req.Age, id)
-	if err != nil {
-		return 0, err
-	}
-	return res.RowsAffected()
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		before, err := scanPerson(tx.QueryRowContext(ctx, `
+			SELECT `+personColumns+` FROM people WHERE id=$1 FOR UPDATE
+		`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var key *string
+		if req.LastName != nil {
+			k := dedupe.SurnameKey(*req.LastName)
+			key = &k
+		}
+		after, err := scanPerson(tx.QueryRowContext(ctx, `
+			UPDATE people SET
+				first_name = COALESCE($1, first_name),
+				middle_name = COALESCE($2, middle_name),
+				last_name = COALESCE($3, last_name),
+				gender = COALESCE($4, gender),
+				nationality = COALESCE($5, nationality),
+				age = COALESCE($6, age),
+				dedupe_key = COALESCE($8, dedupe_key),
+				updated_at = NOW()
+			WHERE id=$7
+			RETURNING `+personColumns,
+			req.FirstName, req.MiddleName, req.LastName, req.Gender, req.Nationality, req.Age, id, key))
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(id, 10), audit.ActionUpdate, before, after)
+	})
+	return aff, err
+}
+
+// DeletePerson removes a person together with their emails, friendships,
+// relations and blocks, auditing every row it removes.
+func (s *Store) DeletePerson(ctx context.Context, id int64) (int64, error) {
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		before, err := scanPerson(tx.QueryRowContext(ctx, `
+			SELECT `+personColumns+` FROM people WHERE id=$1 FOR UPDATE
+		`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			DELETE FROM emails WHERE person_id=$1
+			RETURNING id, person_id, email, is_primary, created_at
+		`, id)
+		if err != nil {
+			return err
+		}
+		var emails []models.Email
+		for rows.Next() {
+			var e models.Email
+			if err := rows.Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			emails = append(emails, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, e := range emails {
+			if err := writeAudit(ctx, tx, audit.EntityEmail, strconv.FormatInt(e.ID, 10), audit.ActionDelete, e, nil); err != nil {
+				return err
+			}
+		}
+		if _, err := deleteBlocksOf(ctx, tx, id); err != nil {
+			return err
+		}
+		if _, err := deleteFriendshipsOf(ctx, tx, id); err != nil {
+			return err
+		}
+		if _, err := deleteRelationsOf(ctx, tx, id); err != nil {
+			return err
+		}
+
+		// Redirects to id cascade: ids merged into it stop resolving too.
+		if _, err := tx.ExecContext(ctx, `DELETE FROM people WHERE id=$1`, id); err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityPerson, strconv.FormatInt(id, 10), audit.ActionDelete, before, nil)
+	})
+	return aff, err
 }
 
 // ---------- emails
 
 func (s *Store) InsertEmail(ctx context.Context, personID int64, email string, isPrimary bool) (int64, error) {
-	var id int64
-	err := s.db.QueryRowContext(ctx, `
-		INSERT INTO emails (person_id, email, is_primary) VALUES ($1,$2,$3) RETURNING id
-	`, personID, email, isPrimary).Scan(&id)
-	return id, err
+	var e models.Email
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO emails (person_id, email, is_primary) VALUES ($1,$2,$3)
+			RETURNING id, person_id, email, is_primary, created_at
+		`, personID, email, isPrimary).Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityEmail, strconv.FormatInt(e.ID, 10), audit.ActionCreate, nil, e)
+	})
+	return e.ID, err
 }
 
 func (s *Store) GetEmailByID(ctx context.Context, emailID int64) (models.Email, error) {
@@ -196,36 +308,39 @@ func (s *Store) ListEmails(ctx context.Context, personID int64) ([]models.Email,
 }
 
 func (s *Store) DeleteEmail(ctx context.Context, personID, emailID int64) (int64, error) {
-	res, err := s.db.ExecContext(ctx, `
-		DELETE FROM emails WHERE id=$1 AND person_id=$2
-	`, emailID, personID)
-	if err != nil {
-		return 0, err
-	}
-	return res.RowsAffected()
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var e models.Email
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM emails WHERE id=$1 AND person_id=$2
+			RETURNING id, person_id, email, is_primary, created_at
+		`, emailID, personID).Scan(&e.ID, &e.PersonID, &e.Email, &e.IsPrimary, &e.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityEmail, strconv.FormatInt(e.ID, 10), audit.ActionDelete, e, nil)
+	})
+	return aff, err
 }
 
-func (s *Store) emailsByPersonIDs(ctx context.Context, ids []int64) (map[int64][]models.Email, error) {
+// EmailsByPersonIDs loads the emails of many people in one query, primary
+// first.
+func (s *Store) EmailsByPersonIDs(ctx context.Context, ids []int64) (map[int64][]models.Email, error) {
 	out := make(map[int64][]models.Email, len(ids))
 	if len(ids) == 0 {
 		return out, nil
 	}
 
-	placeholders := make([]string, 0, len(ids))
-	args := make([]any, 0, len(ids))
-	for i, id := range ids {
-		placeholders = append(placeholders, "$"+strconv.Itoa(i+1))
-		args = append(args, id)
-	}
-
-	q := fmt.Sprintf(`
-        SELECT id, person_id, email, is_primary, created_at
-        FROM emails
-        WHERE person_id IN (%s)
-        ORDER BY is_primary DESC, id ASC
-    `, strings.Join(placeholders, ","))
-
-	rows, err := s.db.QueryContext(ctx, q, args...)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, person_id, email, is_primary, created_at
+		FROM emails
+		WHERE person_id = ANY($1)
+		ORDER BY is_primary DESC, id ASC
+	`, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -243,15 +358,45 @@ func (s *Store) emailsByPersonIDs(ctx context.Context, ids []int64) (map[int64][
 
 // ---------- friends
 
+// AddFriend returns ErrBlocked if either person has blocked the other.
 func (s *Store) AddFriend(ctx context.Context, a, b int64) error {
 	u1, u2 := a, b
 	if u1 > u2 {
 		u1, u2 = u2, u1
 	}
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO friendships (user_id, friend_id) VALUES ($1,$2) ON CONFLICT DO NOTHING
-	`, u1, u2)
-	return err
+	inserted := false
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := lockPair(ctx, tx, u1, u2); err != nil {
+			return err
+		}
+		var f models.Friendship
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO friendships (user_id, friend_id)
+			SELECT $1::bigint, $2::bigint
+			WHERE NOT EXISTS (`+blockedBetweenSQL+`)
+			ON CONFLICT DO NOTHING
+			RETURNING user_id, friend_id, created_at
+		`, u1, u2).Scan(&f.UserID, &f.FriendID, &f.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		inserted = true
+		return writeAudit(ctx, tx, audit.EntityFriendship, audit.PairID(u1, u2), audit.ActionCreate, nil, f)
+	})
+	if err != nil || inserted {
+		return err
+	}
+	blocked, err := s.IsBlocked(ctx, a, b)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
 }
 
 func (s *Store) RemoveFriend(ctx context.Context, a, b int64) (int64, error) {
@@ -259,13 +404,75 @@ func (s *Store) RemoveFriend(ctx context.Context, a, b int64) (int64, error) {
 	if u1 > u2 {
 		u1, u2 = u2, u1
 	}
-	res, err := s.db.ExecContext(ctx, `
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		aff, err = deleteFriendship(ctx, tx, u1, u2)
+		return err
+	})
+	return aff, err
+}
+
+// lockPair serialises AddFriend and Block on the same two people, so the
+// block check of one and the friendship removal of the other see each
+// other's commits. The people rows are share-locked first, as MergePeople
+// locks them before moving edges, so a merge involving either person has
+// committed before the pair is read.
+func lockPair(ctx context.Context, tx *sql.Tx, a, b int64) error {
+	u1, u2 := min(a, b), max(a, b)
+	if _, err := tx.ExecContext(ctx, `
+		SELECT 1 FROM people WHERE id IN ($1,$2) ORDER BY id FOR KEY SHARE
+	`, u1, u2); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('pair:' || $1::text || ':' || $2::text))`, u1, u2)
+	return err
+}
+
+// deleteFriendship removes the canonical edge u1 < u2 and audits it.
+func deleteFriendship(ctx context.Context, tx *sql.Tx, u1, u2 int64) (int64, error) {
+	var f models.Friendship
+	err := tx.QueryRowContext(ctx, `
 		DELETE FROM friendships WHERE user_id=$1 AND friend_id=$2
-	`, u1, u2)
+		RETURNING user_id, friend_id, created_at
+	`, u1, u2).Scan(&f.UserID, &f.FriendID, &f.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, err
 	}
-	return res.RowsAffected()
+	return 1, writeAudit(ctx, tx, audit.EntityFriendship, audit.PairID(u1, u2), audit.ActionDelete, f, nil)
+}
+
+// deleteFriendshipsOf removes and audits every friendship of id.
+func deleteFriendshipsOf(ctx context.Context, tx *sql.Tx, id int64) ([]models.Friendship, error) {
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM friendships WHERE user_id=$1 OR friend_id=$1
+		RETURNING user_id, friend_id, created_at
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	var out []models.Friendship
+	for rows.Next() {
+		var f models.Friendship
+		if err := rows.Scan(&f.UserID, &f.FriendID, &f.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, f := range out {
+		if err := writeAudit(ctx, tx, audit.EntityFriendship, audit.PairID(f.UserID, f.FriendID), audit.ActionDelete, f, nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
 }
 
 func (s *Store) ListFriends(ctx context.Context, id int64) ([]models.Person, error) {
@@ -293,3 +500,131 @@ func (s *Store) ListFriends(ctx context.Context, id int64) ([]models.Person, err
 	}
 	return out, nil
 }
+
+// FriendsByPersonIDs loads the friends of many people in one query, each
+// list ordered by id.
+func (s *Store) FriendsByPersonIDs(ctx context.Context, ids []int64) (map[int64][]models.Person, error) {
+	out := make(map[int64][]models.Person, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.owner, p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at
+		FROM (
+			SELECT user_id AS owner, friend_id AS fid FROM friendships WHERE user_id = ANY($1)
+			UNION ALL
+			SELECT friend_id, user_id FROM friendships WHERE friend_id = ANY($1)
+		) f
+		JOIN people p ON p.id = f.fid
+		ORDER BY f.owner, p.id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var owner int64
+		var p models.Person
+		if err := rows.Scan(&owner, &p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out[owner] = append(out[owner], p)
+	}
+	return out, rows.Err()
+}
+
+// friendIDsSQL expands to the ids adjacent to the given placeholder. The two
+// branches keep both friendships indexes usable.
+func friendIDsSQL(ph string) string {
+	return `SELECT friend_id FROM friendships WHERE user_id=` + ph + `
+		UNION ALL
+		SELECT user_id FROM friendships WHERE friend_id=` + ph
+}
+
+func (s *Store) MutualFriends(ctx context.Context, a, b int64, limit, offset int) ([]models.Person, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at
+		FROM people p
+		WHERE p.id IN (`+friendIDsSQL("$1")+`)
+		  AND p.id IN (`+friendIDsSQL("$2")+`)
+		ORDER BY p.id ASC
+		LIMIT $3 OFFSET $4
+	`, a, b, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPeople(rows)
+}
+
+const (
+	suggestNationalityBoost = 0.5
+	suggestSurnameBoost     = 0.5
+)
+
+// FriendSuggestions ranks second-degree connections of id by the number of
+// mutual friends, with a small boost for a shared nationality or surname.
+// Existing friends and anyone in a block relation with id are left out.
+func (s *Store) FriendSuggestions(ctx context.Context, id int64, limit, offset int) ([]models.FriendSuggestion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH f AS (`+friendIDsSQL("$1")+`),
+		fof AS (
+			SELECT fr.friend_id AS cid FROM friendships fr JOIN f ON fr.user_id = f.friend_id
+			UNION ALL
+			SELECT fr.user_id FROM friendships fr JOIN f ON fr.friend_id = f.friend_id
+		),
+		c AS (
+			SELECT cid, COUNT(*) AS mutual
+			FROM fof
+			WHERE cid <> $1
+			  AND cid NOT IN (SELECT friend_id FROM f)
+			  AND cid NOT IN (`+blockedIDsSQL("$1")+`)
+			GROUP BY cid
+		)
+		SELECT p.id, p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age, p.created_at, p.updated_at,
+			c.mutual,
+			c.mutual
+				+ CASE WHEN p.nationality IS NOT NULL AND p.nationality = me.nationality THEN $4::float8 ELSE 0 END
+				+ CASE WHEN LOWER(p.last_name) = LOWER(me.last_name) THEN $5::float8 ELSE 0 END AS score
+		FROM c
+		JOIN people p ON p.id = c.cid
+		CROSS JOIN (SELECT nationality, last_name FROM people WHERE id=$1) me
+		ORDER BY score DESC, c.mutual DESC, p.id ASC
+		LIMIT $2 OFFSET $3
+	`, id, limit, offset, suggestNationalityBoost, suggestSurnameBoost)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.FriendSuggestion
+	for rows.Next() {
+		var fs models.FriendSuggestion
+		p := &fs.Person
+		if err := rows.Scan(&p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt,
+			&fs.MutualFriends, &fs.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, fs)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) PersonExists(ctx context.Context, id int64) (bool, error) {
+	var ok bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM people WHERE id=$1)`, id).Scan(&ok)
+	return ok, err
+}
+
+func scanPeople(rows *sql.Rows) ([]models.Person, error) {
+	var out []models.Person
+	for rows.Next() {
+		var p models.Person
+		if err := rows.Scan(&p.ID, &p.FirstName, &p.MiddleName, &p.LastName, &p.Gender, &p.Nationality, &p.Age, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
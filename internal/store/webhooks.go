@@ -0,0 +1,340 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+	"github.com/Kirill-Pinyaev/people-api/internal/webhooks"
+)
+
+// writeEvent appends a domain event to the outbox and fans it out to every
+// active subscription interested in its type, all inside tx.
+func writeEvent(ctx context.Context, tx *sql.Tx, eventType, entityID string, reqID *string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		WITH e AS (
+			INSERT INTO outbox_events (type, entity_id, request_id, payload)
+			VALUES ($1,$2,$3,$4)
+			RETURNING id
+		)
+		INSERT INTO webhook_deliveries (event_id, subscription_id)
+		SELECT e.id, s.id FROM e, webhook_subscriptions s
+		WHERE s.active AND (s.event_types = '' OR $1::text = ANY(string_to_array(s.event_types, ' ')))
+	`, eventType, entityID, reqID, string(payload))
+	return err
+}
+
+// ---------- subscriptions
+
+const webhookColumns = `id, url, event_types, active, created_at, updated_at, secret`
+
+func scanWebhook(row interface{ Scan(...any) error }) (models.WebhookSubscription, error) {
+	var w models.WebhookSubscription
+	var types string
+	err := row.Scan(&w.ID, &w.URL, &types, &w.Active, &w.CreatedAt, &w.UpdatedAt, &w.Secret)
+	w.EventTypes = strings.Fields(types)
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+	return w, err
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, url, secret string, eventTypes []string) (models.WebhookSubscription, error) {
+	var w models.WebhookSubscription
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		w, err = scanWebhook(tx.QueryRowContext(ctx, `
+			INSERT INTO webhook_subscriptions (url, secret, event_types) VALUES ($1,$2,$3)
+			RETURNING `+webhookColumns, url, secret, strings.Join(eventTypes, " ")))
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityWebhook, strconv.FormatInt(w.ID, 10), audit.ActionCreate, nil, w)
+	})
+	return w, err
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id int64) (models.WebhookSubscription, error) {
+	return scanWebhook(s.db.QueryRowContext(ctx, `
+		SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id=$1
+	`, id))
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.WebhookSubscription
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// UpdateWebhook applies the non-nil fields of req. It returns sql.ErrNoRows
+// if the subscription does not exist.
+func (s *Store) UpdateWebhook(ctx context.Context, id int64, req models.UpdateWebhookRequest) (models.WebhookSubscription, error) {
+	var types *string
+	if req.EventTypes != nil {
+		t := strings.Join(*req.EventTypes, " ")
+		types = &t
+	}
+	var after models.WebhookSubscription
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		before, err := scanWebhook(tx.QueryRowContext(ctx, `
+			SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id=$1 FOR UPDATE
+		`, id))
+		if err != nil {
+			return err
+		}
+		after, err = scanWebhook(tx.QueryRowContext(ctx, `
+			UPDATE webhook_subscriptions SET
+				url = COALESCE($1, url),
+				event_types = COALESCE($2, event_types),
+				active = COALESCE($3, active),
+				updated_at = NOW()
+			WHERE id=$4
+			RETURNING `+webhookColumns,
+			req.URL, types, req.Active, id))
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, tx, audit.EntityWebhook, strconv.FormatInt(id, 10), audit.ActionUpdate, before, after)
+	})
+	return after, err
+}
+
+// DeleteWebhook removes the subscription along with its delivery log.
+func (s *Store) DeleteWebhook(ctx context.Context, id int64) (int64, error) {
+	var aff int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		w, err := scanWebhook(tx.QueryRowContext(ctx, `
+			DELETE FROM webhook_subscriptions WHERE id=$1 RETURNING `+webhookColumns, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		aff = 1
+		return writeAudit(ctx, tx, audit.EntityWebhook, strconv.FormatInt(id, 10), audit.ActionDelete, w, nil)
+	})
+	return aff, err
+}
+
+// ---------- deliveries
+
+const deliveryColumns = `d.id, d.event_id, e.type, d.subscription_id, d.status, d.attempts, d.next_attempt_at,
+	d.last_status, d.last_error, d.delivered_at, d.created_at`
+
+func scanDelivery(row interface{ Scan(...any) error }) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.EventID, &d.EventType, &d.SubscriptionID, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatus, &d.LastError, &d.DeliveredAt, &d.CreatedAt)
+	return d, err
+}
+
+// ListWebhookDeliveries returns a subscription's deliveries, newest first,
+// optionally restricted to one status.
+func (s *Store) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int) ([]models.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id
+		WHERE d.subscription_id=$1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4
+	`, subscriptionID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ReplayWebhookDelivery queues a delivery to be sent again from scratch,
+// whatever its current status. It returns sql.ErrNoRows if the delivery
+// does not belong to the subscription.
+func (s *Store) ReplayWebhookDelivery(ctx context.Context, subscriptionID, id int64) (models.WebhookDelivery, error) {
+	return scanDelivery(s.db.QueryRowContext(ctx, `
+		WITH d AS (
+			UPDATE webhook_deliveries SET
+				status = 'pending', attempts = 0, next_attempt_at = NOW(),
+				last_status = NULL, last_error = NULL, delivered_at = NULL
+			WHERE id=$1 AND subscription_id=$2
+			RETURNING *
+		)
+		SELECT `+deliveryColumns+` FROM d JOIN outbox_events e ON e.id = d.event_id
+	`, id, subscriptionID))
+}
+
+// ReplayWebhookDeliveries requeues a subscription's dead-lettered
+// deliveries, or, when since is set, every delivery of an event that
+// occurred at or after since.
+func (s *Store) ReplayWebhookDeliveries(ctx context.Context, subscriptionID int64, since *time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries d SET
+			status = 'pending', attempts = 0, next_attempt_at = NOW(),
+			last_status = NULL, last_error = NULL, delivered_at = NULL
+		FROM outbox_events e
+		WHERE e.id = d.event_id AND d.subscription_id=$1
+		  AND (($2::timestamptz IS NULL AND d.status = 'dead') OR e.occurred_at >= $2::timestamptz)
+	`, subscriptionID, since)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ---------- dispatcher
+
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhooks.Job, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			  AND subscription_id IN (SELECT id FROM webhook_subscriptions WHERE active)
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d SET next_attempt_at = NOW() + make_interval(secs => $2)
+			FROM due WHERE d.id = due.id
+			RETURNING d.id, d.attempts, d.event_id, d.subscription_id
+		)
+		SELECT c.id, c.attempts, s.url, s.secret,
+			e.id, e.type, e.occurred_at, e.entity_id, e.request_id, e.payload
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		JOIN outbox_events e ON e.id = c.event_id
+		ORDER BY e.id
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []webhooks.Job
+	for rows.Next() {
+		var j webhooks.Job
+		var data []byte
+		if err := rows.Scan(&j.DeliveryID, &j.Attempts, &j.URL, &j.Secret,
+			&j.Event.ID, &j.Event.Type, &j.Event.OccurredAt, &j.Event.EntityID, &j.Event.RequestID, &data); err != nil {
+			return nil, err
+		}
+		j.Event.Data = data
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) MarkWebhookDelivered(ctx context.Context, id int64, status int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = 'delivered', attempts = attempts + 1, last_status = $2,
+			last_error = NULL, delivered_at = NOW()
+		WHERE id=$1
+	`, id, status)
+	return err
+}
+
+func (s *Store) FailWebhookDelivery(ctx context.Context, id int64, status *int, msg string, retryIn time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = CASE WHEN $4::float8 > 0 THEN 'pending' ELSE 'dead' END,
+			attempts = attempts + 1,
+			next_attempt_at = NOW() + make_interval(secs => $4::float8),
+			last_status = $2, last_error = $3
+		WHERE id=$1
+	`, id, status, msg, retryIn.Seconds())
+	return err
+}
+
+// ---------- change feed
+
+const outboxColumns = `id, tx_id, type, occurred_at, entity_id, request_id, payload`
+
+// finishedTx selects events whose transaction is older than every running
+// one. Only those are final: a running transaction may still commit events
+// with smaller ids.
+const finishedTx = `tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint`
+
+func scanOutboxEvents(rows *sql.Rows) ([]models.OutboxEvent, error) {
+	defer rows.Close()
+	var out []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.TxID, &e.Type, &e.OccurredAt, &e.EntityID, &e.RequestID, &data); err != nil {
+			return nil, err
+		}
+		e.Data = data
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// OutboxEventsAfter returns up to limit events that follow event after in
+// the change feed. The feed orders events by transaction, then id, and
+// holds back a transaction's events until every older one has finished, so
+// its order never changes once read. An unknown after, e.g. 0, starts from
+// the beginning.
+func (s *Store) OutboxEventsAfter(ctx context.Context, after int64, limit int) ([]models.OutboxEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+outboxColumns+` FROM outbox_events
+		WHERE (tx_id, id) > (COALESCE((SELECT tx_id FROM outbox_events WHERE id = $1), -1), $1)
+		  AND `+finishedTx+`
+		ORDER BY tx_id, id LIMIT $2
+	`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOutboxEvents(rows)
+}
+
+// OutboxEventTxID returns the transaction that wrote event id, or -1 if
+// there is no such event.
+func (s *Store) OutboxEventTxID(ctx context.Context, id int64) (int64, error) {
+	var txID int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE((SELECT tx_id FROM outbox_events WHERE id = $1), -1)
+	`, id).Scan(&txID)
+	return txID, err
+}
+
+// LatestOutboxEventID returns the last event of the change feed as it
+// stands now, 0 if it is empty.
+func (s *Store) LatestOutboxEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE((
+			SELECT id FROM outbox_events WHERE `+finishedTx+` ORDER BY tx_id DESC, id DESC LIMIT 1
+		), 0)
+	`).Scan(&id)
+	return id, err
+}
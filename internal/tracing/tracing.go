@@ -0,0 +1,110 @@
+// Package tracing sets up OpenTelemetry tracing with W3C trace context
+// propagation.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceName = "people-api"
+
+// Tracer is the tracer for spans created by this module.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/Kirill-Pinyaev/people-api")
+}
+
+type Options struct {
+	// Exporter is "otlp", "console" (stdout) or "none", the default.
+	Exporter string
+	// OTLPProtocol is "grpc", the default, or "http/protobuf".
+	OTLPProtocol string
+	// OTLPEndpoint overrides the exporter's default endpoint.
+	OTLPEndpoint string
+}
+
+// Setup installs the global tracer provider and propagator. The returned
+// func flushes buffered spans.
+func Setup(ctx context.Context, o Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	var exp sdktrace.SpanExporter
+	switch v := strings.ToLower(o.Exporter); v {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "console":
+		exp, err = stdouttrace.New()
+	case "otlp":
+		switch o.OTLPProtocol {
+		case "", "grpc":
+			var opts []otlptracegrpc.Option
+			if o.OTLPEndpoint != "" {
+				opts = append(opts, otlptracegrpc.WithEndpointURL(o.OTLPEndpoint))
+			}
+			exp, err = otlptracegrpc.New(ctx, opts...)
+		case "http/protobuf":
+			var opts []otlptracehttp.Option
+			if o.OTLPEndpoint != "" {
+				opts = append(opts, otlptracehttp.WithEndpointURL(o.OTLPEndpoint))
+			}
+			exp, err = otlptracehttp.New(ctx, opts...)
+		default:
+			return nil, fmt.Errorf("otlp protocol %q: want grpc or http/protobuf", o.OTLPProtocol)
+		}
+	default:
+		return nil, fmt.Errorf("exporter %q: want otlp, console or none", v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Inject writes the trace context of ctx into outbound request headers.
+func Inject(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// RouteMiddleware names the server span started by otelhttp after the chi
+// route pattern, which is only known once routing is done.
+func RouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		rc := chi.RouteContext(r.Context())
+		if rc == nil || rc.RoutePattern() == "" {
+			return
+		}
+		route := rc.RoutePattern()
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+}
@@ -0,0 +1,173 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+// Job is one claimed delivery of an event to a subscription.
+type Job struct {
+	DeliveryID int64
+	Attempts   int
+	URL        string
+	Secret     string
+	Event      models.OutboxEvent
+}
+
+type Store interface {
+	// ClaimWebhookDeliveries leases up to limit due deliveries so that other
+	// dispatchers skip them until lease runs out.
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Job, error)
+	MarkWebhookDelivered(ctx context.Context, id int64, status int) error
+	// FailWebhookDelivery records a failed attempt. A zero retryIn moves the
+	// delivery to the dead-letter state.
+	FailWebhookDelivery(ctx context.Context, id int64, status *int, msg string, retryIn time.Duration) error
+}
+
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 20
+	DefaultMaxAttempts  = 10
+
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+	maxErrorLen = 500
+)
+
+type Dispatcher struct {
+	Store  Store
+	Client *http.Client
+
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is how many failed attempts dead-letter a delivery.
+	MaxAttempts int
+}
+
+func NewDispatcher(st Store, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		Store:        st,
+		Client:       client,
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+		MaxAttempts:  DefaultMaxAttempts,
+	}
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	t := time.NewTicker(d.PollInterval)
+	defer t.Stop()
+	for {
+		n, err := d.dispatchBatch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "webhooks: dispatch", "err", err)
+		}
+		if n == d.BatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	// The lease outlives a full batch of timed-out requests.
+	lease := 2 * d.Client.Timeout
+	if lease <= 0 {
+		lease = time.Minute
+	}
+	jobs, err := d.Store.ClaimWebhookDeliveries(ctx, d.BatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, j)
+		}()
+	}
+	wg.Wait()
+	return len(jobs), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, j Job) {
+	status, err := d.post(ctx, j)
+	if err == nil {
+		if err := d.Store.MarkWebhookDelivered(ctx, j.DeliveryID, status); err != nil {
+			slog.ErrorContext(ctx, "webhooks: mark delivered", "delivery_id", j.DeliveryID, "err", err)
+		}
+		return
+	}
+
+	var statusPtr *int
+	if status != 0 {
+		statusPtr = &status
+	}
+	var retryIn time.Duration
+	if j.Attempts+1 < d.MaxAttempts {
+		retryIn = Backoff(j.Attempts + 1)
+	}
+	msg := err.Error()
+	if len(msg) > maxErrorLen {
+		msg = msg[:maxErrorLen]
+	}
+	if err := d.Store.FailWebhookDelivery(ctx, j.DeliveryID, statusPtr, msg, retryIn); err != nil {
+		slog.ErrorContext(ctx, "webhooks: mark failed", "delivery_id", j.DeliveryID, "err", err)
+	}
+}
+
+// post sends the event and reports the response status, or 0 if none was
+// received. Any non-2xx status is an error.
+func (d *Dispatcher) post(ctx context.Context, j Job) (int, error) {
+	body, err := json.Marshal(j.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, j.Event.Type)
+	req.Header.Set(EventIDHeader, strconv.FormatInt(j.Event.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(j.Secret, time.Now().Unix(), body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff is the delay before retry n (1-based): exponential from 30s,
+// capped at 6h, with up to 20% jitter.
+func Backoff(n int) time.Duration {
+	d := maxBackoff
+	if n < 20 {
+		d = min(baseBackoff<<(n-1), maxBackoff)
+	}
+	return d + rand.N(d/5+1)
+}
@@ -0,0 +1,84 @@
+// Package webhooks delivers domain events from the outbox to subscribed
+// HTTP endpoints.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/audit"
+)
+
+const (
+	PersonCreated     = "person.created"
+	PersonUpdated     = "person.updated"
+	PersonDeleted     = "person.deleted"
+	EmailCreated      = "email.created"
+	EmailUpdated      = "email.updated"
+	EmailDeleted      = "email.deleted"
+	FriendshipCreated = "friendship.created"
+	FriendshipDeleted = "friendship.deleted"
+)
+
+var AllEventTypes = []string{
+	PersonCreated, PersonUpdated, PersonDeleted,
+	EmailCreated, EmailUpdated, EmailDeleted,
+	FriendshipCreated, FriendshipDeleted,
+}
+
+func ValidEventType(t string) bool {
+	for _, e := range AllEventTypes {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+var actionSuffix = map[string]string{
+	audit.ActionCreate: "created",
+	audit.ActionUpdate: "updated",
+	audit.ActionDelete: "deleted",
+}
+
+// EventType maps an audited mutation to the event published for it. Entities
+// that are not part of the public event stream report false.
+func EventType(entity, action string) (string, bool) {
+	suffix, ok := actionSuffix[action]
+	if !ok {
+		return "", false
+	}
+	t := entity + "." + suffix
+	return t, ValidEventType(t)
+}
+
+const (
+	EventHeader     = "Webhook-Event"
+	EventIDHeader   = "Webhook-Id"
+	SignatureHeader = "Webhook-Signature"
+
+	secretPrefix = "whsec_"
+)
+
+// Sign returns the signature header value for body sent at unix time ts:
+// "t=<ts>,v1=<hex HMAC-SHA256 of "<ts>.<body>">". Receivers should recompute
+// it and reject stale timestamps.
+func Sign(secret string, ts int64, body []byte) string {
+	t := strconv.FormatInt(ts, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func GenerateSecret() (string, error) {
+	var b [24]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS friendships;
+DROP TABLE IF EXISTS emails;
+DROP TABLE IF EXISTS people;
+DROP FUNCTION IF EXISTS set_updated_at();
@@ -0,0 +1 @@
+-- No-op, see 002_seed.up.sql.
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_friendships_friend_id;
@@ -0,0 +1,3 @@
+-- friendships(user_id, friend_id) is already covered by the UNIQUE constraint;
+-- lookups from the friend_id side need their own index.
+CREATE INDEX IF NOT EXISTS idx_friendships_friend_id ON friendships(friend_id);
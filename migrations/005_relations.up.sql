@@ -0,0 +1,14 @@
+-- Typed relationships other than friendship. Friendships stay in their own
+-- table; the API presents them as relations of type "friend".
+CREATE TABLE IF NOT EXISTS relations (
+    from_id BIGINT NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    to_id BIGINT NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('spouse', 'sibling', 'colleague', 'parent', 'manager')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_id <> to_id),
+    -- symmetric kinds are stored once, smaller id first
+    CHECK (kind IN ('parent', 'manager') OR from_id < to_id),
+    PRIMARY KEY (from_id, to_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_relations_to_id ON relations(to_id, kind);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trg_emails_history ON emails;
+DROP TRIGGER IF EXISTS trg_people_history ON people;
+DROP FUNCTION IF EXISTS emails_history_capture();
+DROP FUNCTION IF EXISTS people_history_capture();
+DROP TABLE IF EXISTS emails_history;
+DROP TABLE IF EXISTS people_history;
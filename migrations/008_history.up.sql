@@ -0,0 +1,99 @@
+-- Full versioning of people and emails, maintained by triggers next to
+-- set_updated_at. Versions use clock_timestamp() so several writes in one
+-- transaction still get distinct, ordered validity ranges.
+
+CREATE TABLE IF NOT EXISTS people_history (
+    person_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    op TEXT NOT NULL CHECK (op IN ('insert', 'update', 'delete')),
+    first_name TEXT NOT NULL,
+    middle_name TEXT,
+    last_name TEXT NOT NULL,
+    gender TEXT,
+    nationality TEXT,
+    age INT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ,
+    PRIMARY KEY (person_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS emails_history (
+    email_id BIGINT NOT NULL,
+    person_id BIGINT NOT NULL,
+    email TEXT NOT NULL,
+    is_primary BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_emails_history_person ON emails_history(person_id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_emails_history_open ON emails_history(email_id) WHERE valid_to IS NULL;
+
+CREATE OR REPLACE FUNCTION people_history_capture()
+RETURNS TRIGGER AS $$
+DECLARE
+  ts TIMESTAMPTZ := clock_timestamp();
+  rec people%ROWTYPE;
+  next_version INT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    rec := OLD;
+  ELSE
+    rec := NEW;
+  END IF;
+
+  UPDATE people_history SET valid_to = ts WHERE person_id = rec.id AND valid_to IS NULL;
+  SELECT COALESCE(MAX(version), 0) + 1 INTO next_version FROM people_history WHERE person_id = rec.id;
+
+  INSERT INTO people_history (person_id, version, op, first_name, middle_name, last_name, gender, nationality, age,
+                              created_at, updated_at, valid_from, valid_to)
+  VALUES (rec.id, next_version, LOWER(TG_OP), rec.first_name, rec.middle_name, rec.last_name, rec.gender,
+          rec.nationality, rec.age, rec.created_at, rec.updated_at, ts,
+          CASE WHEN TG_OP = 'DELETE' THEN ts END);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_people_history ON people;
+CREATE TRIGGER trg_people_history
+AFTER INSERT OR UPDATE OR DELETE ON people
+FOR EACH ROW EXECUTE FUNCTION people_history_capture();
+
+CREATE OR REPLACE FUNCTION emails_history_capture()
+RETURNS TRIGGER AS $$
+DECLARE
+  ts TIMESTAMPTZ := clock_timestamp();
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') THEN
+    UPDATE emails_history SET valid_to = ts WHERE email_id = OLD.id AND valid_to IS NULL;
+  END IF;
+  IF TG_OP IN ('INSERT', 'UPDATE') THEN
+    INSERT INTO emails_history (email_id, person_id, email, is_primary, created_at, valid_from)
+    VALUES (NEW.id, NEW.person_id, NEW.email, NEW.is_primary, NEW.created_at, ts);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_emails_history ON emails;
+CREATE TRIGGER trg_emails_history
+AFTER INSERT OR UPDATE OR DELETE ON emails
+FOR EACH ROW EXECUTE FUNCTION emails_history_capture();
+
+-- Existing rows become version 1, valid from now: what they looked like
+-- before this migration was never recorded, and emails carry no updated_at
+-- to say when they last changed. Reads before this point find no history.
+INSERT INTO people_history (person_id, version, op, first_name, middle_name, last_name, gender, nationality, age,
+                            created_at, updated_at, valid_from)
+SELECT p.id, 1, 'insert', p.first_name, p.middle_name, p.last_name, p.gender, p.nationality, p.age,
+       p.created_at, p.updated_at, NOW()
+FROM people p
+WHERE NOT EXISTS (SELECT 1 FROM people_history h WHERE h.person_id = p.id);
+
+INSERT INTO emails_history (email_id, person_id, email, is_primary, created_at, valid_from)
+SELECT e.id, e.person_id, e.email, e.is_primary, e.created_at, NOW()
+FROM emails e
+WHERE NOT EXISTS (SELECT 1 FROM emails_history h WHERE h.email_id = e.id);
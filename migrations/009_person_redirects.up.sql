@@ -0,0 +1,9 @@
+-- Left behind when a person is merged into another, so old ids keep
+-- resolving. from_id has no foreign key: that person no longer exists.
+CREATE TABLE IF NOT EXISTS person_redirects (
+    from_id BIGINT PRIMARY KEY,
+    to_id BIGINT NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_person_redirects_to_id ON person_redirects(to_id);
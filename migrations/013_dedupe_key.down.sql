@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_people_dedupe_key;
+ALTER TABLE people DROP COLUMN IF EXISTS dedupe_key;
@@ -0,0 +1,9 @@
+-- people.dedupe_key is dedupe.SurnameKey(last_name): the surname
+-- transliterated to Latin, letters only, first three. Duplicate detection only
+-- scores people who share it, or an email, with someone else. Unicode letter
+-- classes and case folding have no locale-independent SQL equivalent, so the
+-- key is computed in Go: the store writes it together with last_name, and the
+-- migrator fills rows that predate this migration.
+ALTER TABLE people ADD COLUMN IF NOT EXISTS dedupe_key TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_people_dedupe_key ON people (dedupe_key);
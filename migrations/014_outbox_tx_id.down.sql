@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_events_feed;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS tx_id;
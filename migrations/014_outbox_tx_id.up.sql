@@ -0,0 +1,8 @@
+-- The change feed orders events by the transaction that wrote them and
+-- only serves transactions older than every running one, so a slow commit
+-- cannot slip in behind a client's cursor. Existing events all committed
+-- long ago and sort first.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS tx_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE outbox_events ALTER COLUMN tx_id SET DEFAULT pg_current_xact_id()::text::bigint;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_feed ON outbox_events(tx_id, id);
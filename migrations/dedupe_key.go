@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/dedupe"
+)
+
+// dedupeKeyVersion is the migration that adds people.dedupe_key.
+const dedupeKeyVersion = 13
+
+// fillDedupeKeys computes people.dedupe_key for rows that do not have one:
+// those written before migration 013, which cannot compute dedupe.SurnameKey
+// in SQL. Filling a derived column is not a change to the person, so the
+// updated_at and history triggers are off while it runs.
+func (m *Migrator) fillDedupeKeys(ctx context.Context) error {
+	version, _, err := m.Version()
+	if err != nil || version < dedupeKeyVersion {
+		return err
+	}
+	var missing bool
+	err = m.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM people WHERE dedupe_key IS NULL)`).Scan(&missing)
+	if err != nil || !missing {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE people DISABLE TRIGGER trg_people_updated, DISABLE TRIGGER trg_people_history
+	`); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id, last_name FROM people WHERE dedupe_key IS NULL`)
+	if err != nil {
+		return err
+	}
+	var (
+		ids  []int64
+		keys []string
+	)
+	for rows.Next() {
+		var id int64
+		var lastName string
+		if err := rows.Scan(&id, &lastName); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		keys = append(keys, dedupe.SurnameKey(lastName))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE people p SET dedupe_key = k.key
+		FROM unnest($1::bigint[], $2::text[]) AS k(id, key)
+		WHERE p.id = k.id
+	`, ids, keys); err != nil {
+		return fmt.Errorf("fill dedupe keys: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE people ENABLE TRIGGER trg_people_updated, ENABLE TRIGGER trg_people_history
+	`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
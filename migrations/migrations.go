@@ -0,0 +1,162 @@
+// Package migrations embeds the schema migrations and applies them with
+// golang-migrate, keeping its schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed *.up.sql *.down.sql
+var files embed.FS
+
+//go:embed seed.sql
+var seedSQL string
+
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// All lists the embedded migrations in order.
+func All() []Migration {
+	names, _ := fs.Glob(files, "*.up.sql")
+	var out []Migration
+	for _, n := range names {
+		num, name, _ := strings.Cut(strings.TrimSuffix(n, ".up.sql"), "_")
+		v, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, Migration{Version: uint(v), Name: name})
+	}
+	slices.SortFunc(out, func(a, b Migration) int { return int(a.Version) - int(b.Version) })
+	return out
+}
+
+// Latest is the version a fully migrated database is at.
+func Latest() uint {
+	all := All()
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// Migrator applies the embedded migrations over its own connection, which
+// Close releases. Concurrent migrators wait on an advisory lock.
+type Migrator struct {
+	m  *migrate.Migrate
+	db *sql.DB
+}
+
+func Open(dsn string) (*Migrator, error) {
+	src, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	db := stdlib.OpenDB(*cfg)
+	drv, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", drv)
+	if err != nil {
+		drv.Close()
+		return nil, err
+	}
+	m.Log = logger{}
+	return &Migrator{m: m, db: db}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up() error {
+	if err := ignoreNoChange(m.m.Up()); err != nil {
+		return err
+	}
+	return m.fillDedupeKeys(context.Background())
+}
+
+// Down rolls back the last n migrations.
+func (m *Migrator) Down(n int) error {
+	return ignoreNoChange(m.m.Steps(-n))
+}
+
+// To migrates up or down to version.
+func (m *Migrator) To(version uint) error {
+	if err := ignoreNoChange(m.m.Migrate(version)); err != nil {
+		return err
+	}
+	return m.fillDedupeKeys(context.Background())
+}
+
+// Version reports the current version, 0 for an empty database. dirty is
+// true after a migration failed half way.
+func (m *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// Seed loads the demo data unless the database already has people, and
+// reports whether it did.
+func Seed(ctx context.Context, db *sql.DB) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM people)`).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx, seedSQL); err != nil {
+		return false, fmt.Errorf("seed: %w", err)
+	}
+	return true, tx.Commit()
+}
+
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) {
+	slog.Info("migrate: " + strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (logger) Verbose() bool { return false }
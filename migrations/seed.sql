@@ -0,0 +1,32 @@
+-- Demo data for local development, loaded by `people-api seed`. It joins on
+-- names rather than ids so it works whatever the sequences are at.
+-- dedupe_key is dedupe.SurnameKey(last_name), spelled out by hand.
+WITH p AS (
+  INSERT INTO people (first_name, last_name, dedupe_key, age, gender, nationality) VALUES
+    ('Ivan', 'Ivanov', 'iva', 28, 'male', 'RU'),
+    ('Anna', 'Ivanova', 'iva', 26, 'female', 'RU'),
+    ('Petr', 'Petrov', 'pet', 34, 'male', 'RU'),
+    ('Olga', 'Sidorova', 'sid', 30, 'female', 'RU'),
+    ('John', 'Smith', 'smi', 40, 'male', 'US'),
+    ('Maria', 'Garcia', 'gar', 29, 'female', 'ES'),
+    ('Luca', 'Rossi', 'ros', 31, 'male', 'IT'),
+    ('Sofia', 'Martinez', 'mar', 27, 'female', 'AR'),
+    ('Akira', 'Tanaka', 'tan', 36, 'male', 'JP'),
+    ('Emma', 'Johnson', 'joh', 33, 'female', 'US')
+  RETURNING id, first_name
+), e AS (
+  INSERT INTO emails (person_id, email, is_primary)
+  SELECT p.id, lower(p.first_name) || '.' || v.suffix || '@example.com', true
+  FROM p JOIN (VALUES
+    ('Ivan', 'ivanov'), ('Anna', 'ivanova'), ('Petr', 'petrov'), ('Olga', 'sidorova'),
+    ('John', 'smith'), ('Maria', 'garcia'), ('Luca', 'rossi'), ('Sofia', 'martinez'),
+    ('Akira', 'tanaka'), ('Emma', 'johnson')
+  ) AS v(first_name, suffix) USING (first_name)
+)
+INSERT INTO friendships (user_id, friend_id)
+SELECT LEAST(a.id, b.id), GREATEST(a.id, b.id)
+FROM (VALUES
+  ('Ivan', 'Anna'), ('Ivan', 'Petr'), ('Anna', 'Olga'), ('John', 'Emma'), ('Maria', 'Luca')
+) AS f(a, b)
+JOIN p a ON a.first_name = f.a
+JOIN p b ON b.first_name = f.b;
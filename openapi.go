@@ -0,0 +1,9 @@
+// Package peopleapi holds the repository-root files built into the server.
+package peopleapi
+
+import _ "embed"
+
+// OpenAPI is openapi.yaml, the contract of the REST API.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
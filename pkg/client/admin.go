@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// --------- Audit
+
+// AuditFilter narrows AuditEvents. EntityID requires Entity.
+type AuditFilter struct {
+	Entity   string
+	EntityID string
+	Actor    string
+}
+
+// AuditEvents iterates over the audit log, newest first.
+func (c *Client) AuditEvents(ctx context.Context, f AuditFilter, pageSize int) iter.Seq2[AuditEvent, error] {
+	q := url.Values{}
+	for k, v := range map[string]string{"entity": f.Entity, "id": f.EntityID, "actor": f.Actor} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return pages[AuditEvent](ctx, c, "/v1/audit", q, pageSize)
+}
+
+// --------- API keys
+
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var out []APIKey
+	return out, c.do(ctx, http.MethodGet, "/v1/admin/api-keys/", nil, nil, &out)
+}
+
+// CreateAPIKey returns the only copy of the plaintext key.
+func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (IssuedAPIKey, error) {
+	var out IssuedAPIKey
+	return out, c.do(ctx, http.MethodPost, "/v1/admin/api-keys/", nil, req, &out)
+}
+
+// RotateAPIKey replaces the key's secret and returns the new one.
+func (c *Client) RotateAPIKey(ctx context.Context, keyID int64) (IssuedAPIKey, error) {
+	var out IssuedAPIKey
+	return out, c.do(ctx, http.MethodPost, "/v1/admin/api-keys/"+id(keyID)+"/rotate", nil, nil, &out)
+}
+
+func (c *Client) RevokeAPIKey(ctx context.Context, keyID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/admin/api-keys/"+id(keyID), nil, nil, nil)
+}
+
+// --------- Webhooks
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookSubscription, error) {
+	var out []WebhookSubscription
+	return out, c.do(ctx, http.MethodGet, "/v1/admin/webhooks/", nil, nil, &out)
+}
+
+// CreateWebhook returns the only copy of the signing secret.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (IssuedWebhookSubscription, error) {
+	var out IssuedWebhookSubscription
+	return out, c.do(ctx, http.MethodPost, "/v1/admin/webhooks/", nil, req, &out)
+}
+
+func (c *Client) GetWebhook(ctx context.Context, webhookID int64) (WebhookSubscription, error) {
+	var out WebhookSubscription
+	return out, c.do(ctx, http.MethodGet, "/v1/admin/webhooks/"+id(webhookID), nil, nil, &out)
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID int64, req UpdateWebhookRequest) (WebhookSubscription, error) {
+	var out WebhookSubscription
+	return out, c.do(ctx, http.MethodPatch, "/v1/admin/webhooks/"+id(webhookID), nil, req, &out)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/admin/webhooks/"+id(webhookID), nil, nil, nil)
+}
+
+// WebhookDeliveries iterates over a subscription's deliveries, optionally
+// only those with status pending, delivered or dead.
+func (c *Client) WebhookDeliveries(ctx context.Context, webhookID int64, status string, pageSize int) iter.Seq2[WebhookDelivery, error] {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+	return pages[WebhookDelivery](ctx, c, "/v1/admin/webhooks/"+id(webhookID)+"/deliveries", q, pageSize)
+}
+
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (WebhookDelivery, error) {
+	var out WebhookDelivery
+	return out, c.do(ctx, http.MethodPost, "/v1/admin/webhooks/"+id(webhookID)+"/deliveries/"+id(deliveryID)+"/replay", nil, nil, &out)
+}
+
+// ReplayWebhook requeues the dead-lettered deliveries, or with a non-zero
+// since every event from then on, and returns how many were queued.
+func (c *Client) ReplayWebhook(ctx context.Context, webhookID int64, since time.Time) (int64, error) {
+	q := url.Values{}
+	if !since.IsZero() {
+		q.Set("since", since.Format(time.RFC3339))
+	}
+	var out struct {
+		Replayed int64 `json:"replayed"`
+	}
+	err := c.do(ctx, http.MethodPost, "/v1/admin/webhooks/"+id(webhookID)+"/replay", q, nil, &out)
+	return out.Replayed, err
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WatchOptions selects the change feed. After resumes past an event id;
+// empty Types means every event type.
+type WatchOptions struct {
+	After int64
+	Types []string
+}
+
+// Watch follows /v1/changes/stream and calls fn for every event until ctx is
+// done or fn fails. Dropped connections are resumed from the last event seen;
+// errors from the server, such as a bad type filter, are returned.
+// /v1/changes/ws carries the same events and has no method of its own.
+func (c *Client) Watch(ctx context.Context, o WatchOptions, fn func(Event) error) error {
+	query := url.Values{}
+	if len(o.Types) > 0 {
+		query.Set("types", strings.Join(o.Types, ","))
+	}
+	after, retry := o.After, 2*time.Second
+	for {
+		err := c.stream(ctx, query, &after, &retry, fn)
+		var herr handlerError
+		var apiErr *Error
+		switch {
+		case errors.As(err, &herr):
+			return herr.err
+		case errors.As(err, &apiErr):
+			return err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// handlerError marks a failure of the caller's fn, which ends Watch instead
+// of triggering a reconnect.
+type handlerError struct{ err error }
+
+func (e handlerError) Error() string { return e.err.Error() }
+
+// stream reads one SSE connection, advancing after past each delivered
+// event.
+func (c *Client) stream(ctx context.Context, query url.Values, after *int64, retry *time.Duration, fn func(Event) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/v1/changes/stream", query, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *after > 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(*after, 10))
+	}
+	// The stream outlives any client-wide timeout.
+	hc := *c.httpClient()
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	var data strings.Builder
+	for sc.Scan() {
+		line := sc.Text()
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var e Event
+			if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+				return err
+			}
+			data.Reset()
+			if err := fn(e); err != nil {
+				return handlerError{err}
+			}
+			*after = e.ID
+		case field == "data":
+			data.WriteString(value)
+		case field == "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				*retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return sc.Err()
+}
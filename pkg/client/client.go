@@ -0,0 +1,230 @@
+// Package client is a typed Go client for the People API. Its types are
+// aliases of the server's models; a test walks the router and openapi.yaml
+// so that every route has a method and every request matches the spec.
+//
+// Reads and deletes are retried after network errors and 429, 502, 503 and
+// 504 responses; creates on routes that honour Idempotency-Key are sent with
+// a fresh key and retried the same way. Failed calls return *Error, which
+// matches ErrNotFound and the other sentinels with errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kirill-Pinyaev/people-api/internal/models"
+)
+
+type (
+	Person                    = models.Person
+	Email                     = models.Email
+	PersonVersion             = models.PersonVersion
+	DuplicateCandidate        = models.DuplicateCandidate
+	FriendSuggestion          = models.FriendSuggestion
+	Friendship                = models.Friendship
+	Path                      = models.Path
+	Network                   = models.Network
+	NetworkNode               = models.NetworkNode
+	Relation                  = models.Relation
+	APIKey                    = models.APIKey
+	IssuedAPIKey              = models.IssuedAPIKey
+	WebhookSubscription       = models.WebhookSubscription
+	IssuedWebhookSubscription = models.IssuedWebhookSubscription
+	WebhookDelivery           = models.WebhookDelivery
+	AuditEvent                = models.AuditEvent
+	Event                     = models.OutboxEvent
+
+	CreatePersonRequest   = models.CreatePersonRequest
+	UpdatePersonRequest   = models.UpdatePersonRequest
+	CreateRelationRequest = models.CreateRelationRequest
+	CreateAPIKeyRequest   = models.CreateAPIKeyRequest
+	CreateWebhookRequest  = models.CreateWebhookRequest
+	UpdateWebhookRequest  = models.UpdateWebhookRequest
+)
+
+const (
+	DefaultMaxRetries = 3
+	retryBaseDelay    = 200 * time.Millisecond
+	retryMaxDelay     = 5 * time.Second
+)
+
+// Client calls one People API deployment. APIKey may be an API key or a JWT;
+// a nil HTTPClient means http.DefaultClient.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+	// MaxRetries bounds the retries of one idempotent call.
+	MaxRetries int
+}
+
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: DefaultMaxRetries,
+	}
+}
+
+// call describes one API request.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	header http.Header
+	// retry marks calls that are safe to repeat.
+	retry bool
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Request, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	return req, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// do sends an idempotent-by-method request and decodes the JSON response
+// into out unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	retry := method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	_, err := c.send(ctx, call{method: method, path: path, query: query, body: body, retry: retry}, out)
+	return err
+}
+
+// create POSTs to a route behind the idempotency middleware, so it can be
+// retried under one Idempotency-Key.
+func (c *Client) create(ctx context.Context, path string, body, out any) error {
+	_, err := c.send(ctx, call{
+		method: http.MethodPost,
+		path:   path,
+		body:   body,
+		header: http.Header{"Idempotency-Key": {newIdempotencyKey()}},
+		retry:  true,
+	}, out)
+	return err
+}
+
+// send performs cl, retrying when allowed, and returns the response headers.
+func (c *Client) send(ctx context.Context, cl call, out any) (http.Header, error) {
+	var body []byte
+	if cl.body != nil {
+		var err error
+		if body, err = json.Marshal(cl.body); err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.roundTrip(ctx, cl, body)
+		if err == nil && resp.StatusCode/100 == 2 {
+			defer resp.Body.Close()
+			if out == nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				return resp.Header, nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return nil, fmt.Errorf("decode %s %s: %w", cl.method, cl.path, err)
+			}
+			return resp.Header, nil
+		}
+
+		var wait time.Duration
+		if err == nil {
+			err = decodeError(resp)
+			resp.Body.Close()
+			wait = retryAfter(resp)
+		}
+		if !cl.retry || attempt >= c.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = backoff(attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) roundTrip(ctx context.Context, cl call, body []byte) (*http.Response, error) {
+	req, err := c.newRequest(ctx, cl.method, cl.path, cl.query, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range cl.header {
+		req.Header[k] = vs
+	}
+	return c.httpClient().Do(req)
+}
+
+// retryable reports transport failures and overload or gateway statuses.
+func retryable(err error) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	s, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || s <= 0 {
+		return 0
+	}
+	return min(time.Duration(s)*time.Second, retryMaxDelay)
+}
+
+// backoff is exponential with full jitter.
+func backoff(attempt int) time.Duration {
+	d := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return time.Duration(rand.Int64N(int64(d))) + time.Millisecond
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	crand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func id(n int64) string { return strconv.FormatInt(n, 10) }
@@ -0,0 +1,274 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	peopleapi "github.com/Kirill-Pinyaev/people-api"
+	"github.com/Kirill-Pinyaev/people-api/internal/app"
+	"github.com/Kirill-Pinyaev/people-api/internal/config"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/router"
+	"github.com/Kirill-Pinyaev/people-api/internal/http/validation"
+	"github.com/Kirill-Pinyaev/people-api/pkg/client"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Paths that are served but deliberately left out of openapi.yaml.
+var unspecified = []string{
+	"/healthz", "/livez", "/readyz", "/metrics",
+	"/openapi.yaml", "/openapi.json", "/docs", "/docs/*",
+}
+
+// Routes, or whole paths, that have no client method, and why.
+var uncovered = map[string]string{
+	"/healthz":           "alias of /livez",
+	"/metrics":           "scraped by Prometheus",
+	"/openapi.json":      "same document as /openapi.yaml",
+	"/docs":              "browser UI",
+	"/docs/*":            "browser UI",
+	"GET /v1/changes/ws": "same events as /v1/changes/stream",
+	"GET /graphql":       "queries are sent with POST",
+}
+
+// TestRoutesMatchSpecAndClient fails when the router, openapi.yaml and the
+// client disagree on routes, methods or success statuses, or when the client
+// sends a request the spec rejects.
+func TestRoutesMatchSpecAndClient(t *testing.T) {
+	served := serverRoutes(t)
+	spec := specOperations(t)
+
+	for _, route := range served {
+		_, path, _ := strings.Cut(route, " ")
+		if _, ok := spec[route]; !ok && !slices.Contains(unspecified, path) {
+			t.Errorf("%s is served but not in openapi.yaml", route)
+		}
+	}
+	for route := range spec {
+		if !slices.Contains(served, route) {
+			t.Errorf("%s is in openapi.yaml but not served", route)
+		}
+	}
+
+	// The fake server answers every route with the spec's success status
+	// after checking the request against the spec.
+	v, err := validation.New(peopleapi.OpenAPI, validation.Options{Requests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	hit := map[string]bool{}
+	mux := chi.NewRouter()
+	for _, route := range served {
+		method, pattern, _ := strings.Cut(route, " ")
+		status := spec[route]
+		if status == 0 {
+			status = http.StatusOK
+		}
+		h := func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hit[route] = true
+			mu.Unlock()
+			if pattern == "/v1/changes/stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				io.WriteString(w, "id: 1\ndata: {\"id\":1}\n\n")
+				return
+			}
+			if status == http.StatusNoContent {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			io.WriteString(w, "null")
+		}
+		// Mounted roots answer with and without the trailing slash.
+		mux.MethodFunc(method, pattern, h)
+		if !strings.HasSuffix(pattern, "*") && pattern != "/" {
+			mux.MethodFunc(method, pattern+"/", h)
+		}
+	}
+	srv := httptest.NewServer(v.Middleware(mux))
+	defer srv.Close()
+
+	c := client.New(srv.URL, "key")
+	c.MaxRetries = 0
+	ctx := context.Background()
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	errStop := errors.New("stop")
+
+	calls := map[string]func() error{
+		"Live":    func() error { return c.Live(ctx) },
+		"Ready":   func() error { _, err := c.Ready(ctx); return err },
+		"OpenAPI": func() error { _, err := c.OpenAPI(ctx); return err },
+
+		"ListPeople":      func() error { _, err := c.ListPeople(ctx); return err },
+		"GetPerson":       func() error { _, err := c.GetPerson(ctx, 1); return err },
+		"GetPersonAsOf":   func() error { _, err := c.GetPersonAsOf(ctx, 1, since); return err },
+		"SearchBySurname": func() error { _, err := c.SearchBySurname(ctx, "Ivanov"); return err },
+		"CreatePerson": func() error {
+			_, err := c.CreatePerson(ctx, client.CreatePersonRequest{FirstName: "Ivan", LastName: "Ivanov"})
+			return err
+		},
+		"UpdatePerson": func() error {
+			_, err := c.UpdatePerson(ctx, 1, client.UpdatePersonRequest{FirstName: str("Ivan")})
+			return err
+		},
+		"DeletePerson":  func() error { return c.DeletePerson(ctx, 1) },
+		"PersonHistory": func() error { return drain(c.PersonHistory(ctx, 1, 0)) },
+		"RevertPerson":  func() error { _, err := c.RevertPerson(ctx, 1, 1); return err },
+		"Duplicates":    func() error { return drain(c.Duplicates(ctx, 0.9, 0)) },
+		"MergePeople":   func() error { _, err := c.MergePeople(ctx, 1, 2); return err },
+
+		"ListEmails":  func() error { _, err := c.ListEmails(ctx, 1); return err },
+		"AddEmail":    func() error { _, err := c.AddEmail(ctx, 1, "ivan@example.com", true); return err },
+		"DeleteEmail": func() error { return c.DeleteEmail(ctx, 1, 2) },
+
+		"ListFriends":       func() error { _, err := c.ListFriends(ctx, 1); return err },
+		"AddFriend":         func() error { return c.AddFriend(ctx, 1, 2) },
+		"RemoveFriend":      func() error { return c.RemoveFriend(ctx, 1, 2) },
+		"MutualFriends":     func() error { return drain(c.MutualFriends(ctx, 1, 2, 0)) },
+		"FriendSuggestions": func() error { return drain(c.FriendSuggestions(ctx, 1, 0)) },
+
+		"ListRelations": func() error { _, err := c.ListRelations(ctx, 1, "parent"); return err },
+		"AddRelation": func() error {
+			return c.AddRelation(ctx, 1, client.CreateRelationRequest{Type: "parent", PersonID: 2})
+		},
+		"RemoveRelation": func() error { return c.RemoveRelation(ctx, 1, "parent", 2) },
+
+		"ListBlocks": func() error { _, err := c.ListBlocks(ctx, 1); return err },
+		"Block":      func() error { return c.Block(ctx, 1, 2) },
+		"Unblock":    func() error { return c.Unblock(ctx, 1, 2) },
+
+		"ShortestPath": func() error { _, err := c.ShortestPath(ctx, 1, 2, 3); return err },
+		"Network":      func() error { _, err := c.Network(ctx, 1, 2); return err },
+		"ExportGraph": func() error {
+			body, err := c.ExportGraph(ctx, client.GraphExportOptions{Format: "dot"})
+			if err == nil {
+				body.Close()
+			}
+			return err
+		},
+
+		"AuditEvents": func() error { return drain(c.AuditEvents(ctx, client.AuditFilter{Entity: "person", EntityID: "1"}, 0)) },
+		"ListAPIKeys": func() error { _, err := c.ListAPIKeys(ctx); return err },
+		"CreateAPIKey": func() error {
+			_, err := c.CreateAPIKey(ctx, client.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"people:read"}})
+			return err
+		},
+		"RotateAPIKey": func() error { _, err := c.RotateAPIKey(ctx, 1); return err },
+		"RevokeAPIKey": func() error { return c.RevokeAPIKey(ctx, 1) },
+
+		"ListWebhooks": func() error { _, err := c.ListWebhooks(ctx); return err },
+		"CreateWebhook": func() error {
+			_, err := c.CreateWebhook(ctx, client.CreateWebhookRequest{URL: "https://example.com/hook"})
+			return err
+		},
+		"GetWebhook": func() error { _, err := c.GetWebhook(ctx, 1); return err },
+		"UpdateWebhook": func() error {
+			_, err := c.UpdateWebhook(ctx, 1, client.UpdateWebhookRequest{URL: str("https://example.com/hook")})
+			return err
+		},
+		"DeleteWebhook":         func() error { return c.DeleteWebhook(ctx, 1) },
+		"WebhookDeliveries":     func() error { return drain(c.WebhookDeliveries(ctx, 1, "dead", 0)) },
+		"ReplayWebhookDelivery": func() error { _, err := c.ReplayWebhookDelivery(ctx, 1, 2); return err },
+		"ReplayWebhook":         func() error { _, err := c.ReplayWebhook(ctx, 1, since); return err },
+
+		"Watch": func() error {
+			err := c.Watch(ctx, client.WatchOptions{After: 1, Types: []string{"person.created"}}, func(client.Event) error { return errStop })
+			if errors.Is(err, errStop) {
+				return nil
+			}
+			return err
+		},
+		"GraphQL": func() error { return c.GraphQL(ctx, "{ person(id: 1) { id } }", nil, nil) },
+	}
+	for name, call := range calls {
+		if err := call(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	for _, route := range served {
+		_, path, _ := strings.Cut(route, " ")
+		if !hit[route] && uncovered[route] == "" && uncovered[path] == "" {
+			t.Errorf("%s has no client method", route)
+		}
+	}
+}
+
+// serverRoutes lists the router's routes as "METHOD /pattern", with the
+// trailing slash of mounted roots trimmed as the spec writes them.
+func serverRoutes(t *testing.T) []string {
+	cfg := config.Default()
+	pgxConfig, err := pgx.ParseConfig(cfg.DB.DSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Building the router does not connect.
+	db := stdlib.OpenDB(*pgxConfig)
+	t.Cleanup(func() { db.Close() })
+	r, err := router.Routes(app.New(cfg, db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	err = chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		out = append(out, method+" "+route)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// specOperations maps each operation in openapi.yaml to its lowest success
+// status.
+func specOperations(t *testing.T) map[string]int {
+	doc, err := openapi3.NewLoader().LoadFromData(peopleapi.OpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]int{}
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			status := 0
+			for code := range op.Responses.Map() {
+				if n, err := strconv.Atoi(code); err == nil && n >= 101 && n < 300 && (status == 0 || n < status) {
+					status = n
+				}
+			}
+			route := method + " " + path
+			if status == 0 {
+				t.Errorf("%s documents no success response", route)
+			}
+			out[route] = status
+		}
+	}
+	return out
+}
+
+// drain reads the first page of seq.
+func drain[T any](seq iter.Seq2[T, error]) error {
+	for _, err := range seq {
+		return err
+	}
+	return nil
+}
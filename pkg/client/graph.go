@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// --------- Graph
+
+// ShortestPath finds the friendship chain between two people within
+// maxDepth hops, or the server default when maxDepth is zero. No path is
+// ErrNotFound.
+func (c *Client) ShortestPath(ctx context.Context, personID, otherID int64, maxDepth int) (Path, error) {
+	var out Path
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/path/"+id(otherID), depthQuery("max_depth", maxDepth), nil, &out)
+}
+
+// Network returns the ego network around the person, depth hops deep or the
+// server default when depth is zero.
+func (c *Client) Network(ctx context.Context, personID int64, depth int) (Network, error) {
+	var out Network
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/network", depthQuery("depth", depth), nil, &out)
+}
+
+// GraphExportOptions selects what ExportGraph returns. Ego cannot be combined
+// with the attribute filters.
+type GraphExportOptions struct {
+	// Format is json, graphml, gexf or dot; empty means json.
+	Format      string
+	Nationality string
+	Gender      string
+	LastName    string
+	Ego         int64
+	Depth       int
+}
+
+// ExportGraph streams the friendship graph in the requested format. The
+// caller closes the body.
+func (c *Client) ExportGraph(ctx context.Context, o GraphExportOptions) (io.ReadCloser, error) {
+	q := depthQuery("depth", o.Depth)
+	for k, v := range map[string]string{"format": o.Format, "nationality": o.Nationality, "gender": o.Gender, "last_name": o.LastName} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if o.Ego != 0 {
+		q.Set("ego", id(o.Ego))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "/v1/graph:export", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Accept")
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp.Body, nil
+}
+
+func depthQuery(name string, n int) url.Values {
+	q := url.Values{}
+	if n > 0 {
+		q.Set(name, strconv.Itoa(n))
+	}
+	return q
+}
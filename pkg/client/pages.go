@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PageSize is the page size iterators ask for when none is given; the
+// server rejects sizes above 500.
+const PageSize = 100
+
+// pages iterates over a paginated list, requesting the next page while the
+// server advertises one with a Link header. Iteration stops after the
+// first error.
+func pages[T any](ctx context.Context, c *Client, path string, query url.Values, size int) iter.Seq2[T, error] {
+	if size <= 0 {
+		size = PageSize
+	}
+	return func(yield func(T, error) bool) {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("limit", strconv.Itoa(size))
+		for offset := 0; ; {
+			q.Set("offset", strconv.Itoa(offset))
+			var page []T
+			h, err := c.send(ctx, call{method: http.MethodGet, path: path, query: q, retry: true}, &page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, v := range page {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			if h.Get("Link") == "" || len(page) == 0 {
+				return
+			}
+			offset += len(page)
+		}
+	}
+}
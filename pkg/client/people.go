@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --------- People
+
+func (c *Client) ListPeople(ctx context.Context) ([]Person, error) {
+	var out []Person
+	return out, c.do(ctx, http.MethodGet, "/v1/people/", nil, nil, &out)
+}
+
+// GetPerson follows the redirect left behind when id was merged into
+// another person.
+func (c *Client) GetPerson(ctx context.Context, personID int64) (Person, error) {
+	var out Person
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID), nil, nil, &out)
+}
+
+// GetPersonAsOf returns the person and their emails as they were at t.
+func (c *Client) GetPersonAsOf(ctx context.Context, personID int64, t time.Time) (Person, error) {
+	var out Person
+	q := url.Values{"as_of": {t.Format(time.RFC3339)}}
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID), q, nil, &out)
+}
+
+// SearchBySurname returns ErrNotFound when nobody has the surname.
+func (c *Client) SearchBySurname(ctx context.Context, lastName string) ([]Person, error) {
+	var out []Person
+	return out, c.do(ctx, http.MethodGet, "/v1/people/surname/"+url.PathEscape(lastName), nil, nil, &out)
+}
+
+func (c *Client) CreatePerson(ctx context.Context, req CreatePersonRequest) (Person, error) {
+	var out Person
+	return out, c.create(ctx, "/v1/people/", req, &out)
+}
+
+// UpdatePerson changes only the non-nil fields of req.
+func (c *Client) UpdatePerson(ctx context.Context, personID int64, req UpdatePersonRequest) (Person, error) {
+	var out Person
+	return out, c.do(ctx, http.MethodPatch, "/v1/people/"+id(personID), nil, req, &out)
+}
+
+// DeletePerson removes the person with their emails, friendships,
+// relations and blocks.
+func (c *Client) DeletePerson(ctx context.Context, personID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/people/"+id(personID), nil, nil, nil)
+}
+
+// --------- History
+
+// PersonHistory iterates over the person's versions, newest first.
+func (c *Client) PersonHistory(ctx context.Context, personID int64, pageSize int) iter.Seq2[PersonVersion, error] {
+	return pages[PersonVersion](ctx, c, "/v1/people/"+id(personID)+"/history", nil, pageSize)
+}
+
+// RevertPerson restores version; a deletion cannot be restored and returns
+// ErrUnprocessable.
+func (c *Client) RevertPerson(ctx context.Context, personID int64, version int) (Person, error) {
+	var out Person
+	return out, c.do(ctx, http.MethodPost, "/v1/people/"+id(personID)+"/revert/"+strconv.Itoa(version), nil, nil, &out)
+}
+
+// --------- Duplicates
+
+// Duplicates iterates over likely duplicate pairs scoring at least minScore;
+// zero means the server default.
+func (c *Client) Duplicates(ctx context.Context, minScore float64, pageSize int) iter.Seq2[DuplicateCandidate, error] {
+	q := url.Values{}
+	if minScore > 0 {
+		q.Set("min_score", strconv.FormatFloat(minScore, 'f', -1, 64))
+	}
+	return pages[DuplicateCandidate](ctx, c, "/v1/people/duplicates", q, pageSize)
+}
+
+// MergePeople merges otherID into personID and returns the survivor. A merge
+// whose relations would break a limit or close a cycle is ErrConflict.
+func (c *Client) MergePeople(ctx context.Context, personID, otherID int64) (Person, error) {
+	var out Person
+	return out, c.do(ctx, http.MethodPost, "/v1/people/"+id(personID)+"/merge/"+id(otherID), nil, nil, &out)
+}
+
+// --------- Emails
+
+func (c *Client) ListEmails(ctx context.Context, personID int64) ([]Email, error) {
+	var out []Email
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/emails", nil, nil, &out)
+}
+
+func (c *Client) AddEmail(ctx context.Context, personID int64, email string, primary bool) (Email, error) {
+	var out Email
+	body := map[string]any{"email": email, "is_primary": primary}
+	return out, c.create(ctx, "/v1/people/"+id(personID)+"/emails", body, &out)
+}
+
+func (c *Client) DeleteEmail(ctx context.Context, personID, emailID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/people/"+id(personID)+"/emails/"+id(emailID), nil, nil, nil)
+}
+
+// --------- Friends
+
+func (c *Client) ListFriends(ctx context.Context, personID int64) ([]Person, error) {
+	var out []Person
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/friends", nil, nil, &out)
+}
+
+func (c *Client) AddFriend(ctx context.Context, personID, friendID int64) error {
+	return c.create(ctx, "/v1/people/"+id(personID)+"/friends/"+id(friendID), nil, nil)
+}
+
+func (c *Client) RemoveFriend(ctx context.Context, personID, friendID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/people/"+id(personID)+"/friends/"+id(friendID), nil, nil, nil)
+}
+
+func (c *Client) MutualFriends(ctx context.Context, personID, otherID int64, pageSize int) iter.Seq2[Person, error] {
+	return pages[Person](ctx, c, "/v1/people/"+id(personID)+"/friends/mutual/"+id(otherID), nil, pageSize)
+}
+
+// FriendSuggestions iterates over friends of friends, best first.
+func (c *Client) FriendSuggestions(ctx context.Context, personID int64, pageSize int) iter.Seq2[FriendSuggestion, error] {
+	return pages[FriendSuggestion](ctx, c, "/v1/people/"+id(personID)+"/friend-suggestions", nil, pageSize)
+}
+
+// --------- Relations
+
+// ListRelations lists the person's relations, only those of the given types
+// if any.
+func (c *Client) ListRelations(ctx context.Context, personID int64, types ...string) ([]Relation, error) {
+	var q url.Values
+	if len(types) > 0 {
+		q = url.Values{"type": {strings.Join(types, ",")}}
+	}
+	var out []Relation
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/relations", q, nil, &out)
+}
+
+func (c *Client) AddRelation(ctx context.Context, personID int64, req CreateRelationRequest) error {
+	return c.do(ctx, http.MethodPost, "/v1/people/"+id(personID)+"/relations", nil, req, nil)
+}
+
+func (c *Client) RemoveRelation(ctx context.Context, personID int64, relType string, otherID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/people/"+id(personID)+"/relations/"+url.PathEscape(relType)+"/"+id(otherID), nil, nil, nil)
+}
+
+// --------- Blocks
+
+func (c *Client) ListBlocks(ctx context.Context, personID int64) ([]Person, error) {
+	var out []Person
+	return out, c.do(ctx, http.MethodGet, "/v1/people/"+id(personID)+"/blocks", nil, nil, &out)
+}
+
+func (c *Client) Block(ctx context.Context, personID, otherID int64) error {
+	return c.do(ctx, http.MethodPost, "/v1/people/"+id(personID)+"/blocks/"+id(otherID), nil, nil, nil)
+}
+
+func (c *Client) Unblock(ctx context.Context, personID, otherID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v1/people/"+id(personID)+"/blocks/"+id(otherID), nil, nil, nil)
+}
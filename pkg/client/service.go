@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// --------- Health
+
+// Live reports whether the server process is up.
+func (c *Client) Live(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/livez", nil, nil, nil)
+}
+
+// Readiness is the /readyz report.
+type Readiness struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Detail any    `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Ready returns the readiness report. A replica that is not ready answers
+// 503, reported as ErrUnavailable together with the decoded report.
+func (c *Client) Ready(ctx context.Context) (Readiness, error) {
+	var out Readiness
+	req, err := c.newRequest(ctx, http.MethodGet, "/readyz", nil, nil)
+	if err != nil {
+		return out, err
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return out, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return out, decodeError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return out, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return out, &Error{StatusCode: resp.StatusCode, Code: "service unavailable", Message: out.Status}
+	}
+	return out, nil
+}
+
+// OpenAPI returns the server's openapi.yaml.
+func (c *Client) OpenAPI(ctx context.Context) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/openapi.yaml", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Accept")
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// --------- GraphQL
+
+// GraphQL runs a query or mutation and decodes its data into out. Errors in
+// the response come back as *GraphQLError, with whatever data there was
+// still decoded.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+		GraphQLError
+	}
+	body := map[string]any{"query": query}
+	if variables != nil {
+		body["variables"] = variables
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", nil, body, &resp); err != nil {
+		return err
+	}
+	if out != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return &resp.GraphQLError
+	}
+	return nil
+}